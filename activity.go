@@ -0,0 +1,49 @@
+package apiary
+
+import (
+	"sort"
+	"time"
+)
+
+// ActivityEntry is a struct of publish event returned by RecentActivity()
+//
+// Description:
+// Api - API blueprint was published to
+// PublishedAt - time of publish
+type ActivityEntry struct {
+	Api         ApiaryApiResponse
+	PublishedAt time.Time
+}
+
+// RecentActivity return latest publish events of user APIs, newest first and
+// no more than limit (all when limit is not positive). apiary.io API has no
+// revision history, so every API has one entry with time of its last update,
+// see GetApisWithTimestamps(). APIs with unknown update time are left out.
+// As with GetApisWithTimestamps() entries are returned along with ApiErrors
+// when some blueprints couldn't be fetched.
+func (a *Apiary) RecentActivity(limit int) (entries []ActivityEntry, err error) {
+	apis, err := a.GetApisWithTimestamps()
+	if _, partial := err.(ApiErrors); err != nil && !partial {
+		return
+	}
+
+	entries = []ActivityEntry{}
+	for _, api := range apis {
+		if api.UpdatedAt != nil {
+			entries = append(entries, ActivityEntry{
+				Api:         api.Api,
+				PublishedAt: *api.UpdatedAt,
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].PublishedAt.After(entries[j].PublishedAt)
+	})
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return
+}
@@ -0,0 +1,70 @@
+package apiary
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_RecentActivity(t *testing.T) {
+	mock := func() {
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionGetApis, httpmock.NewStringResponder(200, `{"apis": [
+			{"apiSubdomain": "old", "apiUpdatedAt": "2017-01-01T00:00:00Z"},
+			{"apiSubdomain": "newest", "apiUpdatedAt": "2017-03-01T00:00:00Z"},
+			{"apiSubdomain": "unknown"},
+			{"apiSubdomain": "newer", "apiUpdatedAt": "2017-02-01T00:00:00Z"}
+		]}`))
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, "unknown"), httpmock.NewStringResponder(200, `{"code": ""}`))
+	}
+
+	activity := func(entries []ActivityEntry) string {
+		names := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			names = append(names, entry.Api.Subdomain+"@"+entry.PublishedAt.Format("2006-01"))
+		}
+
+		return strings.Join(names, ",")
+	}
+
+	t.Run("Sort newest first", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mock()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		entries, err := a.RecentActivity(0)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if s := activity(entries); s != "newest@2017-03,newer@2017-02,old@2017-01" {
+			t.Errorf("Wrong activity: %s", s)
+		}
+	})
+
+	t.Run("Cap at limit", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mock()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		entries, err := a.RecentActivity(2)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if s := activity(entries); s != "newest@2017-03,newer@2017-02" {
+			t.Errorf("Wrong activity: %s", s)
+		}
+	})
+}
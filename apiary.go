@@ -1,15 +1,31 @@
 package apiary
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 )
 
 // ApiaryAPIURL URL of public apiary.io API
 const ApiaryAPIURL = "https://api.apiary.io/"
 
+// ApiBlueprintParserURL URL of public API Blueprint parser service
+const ApiBlueprintParserURL = "https://api.apiblueprint.org/parser"
+
+const (
+	apiaryDocumentationURL = "https://%s.docs.apiary.io/"
+	apiaryMockURL          = "https://%s.apiary-mock.com/"
+)
+
+// DefaultParallelism default number of concurrent requests of batch calls
+const DefaultParallelism = 4
+
+// DefaultMaxPublishBytes default limit of blueprint size for PublishBlueprint
+const DefaultMaxPublishBytes = 10 << 20
+
 const (
 	apiaryActionMe               = "me"
 	apiaryActionGetApis          = "me/apis"
@@ -18,25 +34,44 @@ const (
 	apiaryActionPublishBlueprint = "blueprint/publish/%s"
 )
 
+// EndpointClass is a class of apiary.io endpoints sharing auth scheme
+type EndpointClass int
+
+const (
+	// EndpointModern endpoints authorized with "Authorization: bearer <token>"
+	EndpointModern EndpointClass = iota
+	// EndpointLegacy endpoints authorized with "Authentication: Token <token>"
+	EndpointLegacy
+)
+
+// DefaultBlueprintAuthOrder is an order of auth schemes tried by blueprint
+// fetches when BlueprintAuthOrder option is empty
+var DefaultBlueprintAuthOrder = []EndpointClass{EndpointLegacy, EndpointModern}
+
 // ApiaryMeResponse is a struct of answer to Me() call
 //
 // Description:
 // ID - user id
 // Name - user name
 // URL - user API URL
-// Teams - slice of
-// * ID - team id
-// * Name - team name
-// * URL - team api url
+// Teams - slice of ApiaryTeam
 type ApiaryMeResponse struct {
 	ID    string `json:"userId"`
 	Name  string `json:"userName"`
 	URL   string `json:"userApisUrl"`
-	Teams []struct {
-		ID   string `json:"teamId"`
-		Name string `json:"teamName"`
-		URL  string `json:"teamApisUrl"`
-	}
+	Teams []ApiaryTeam
+}
+
+// ApiaryTeam is a struct of user team in answer to Me() call
+//
+// Description:
+// ID - team id
+// Name - team name
+// URL - team api url
+type ApiaryTeam struct {
+	ID   string `json:"teamId"`
+	Name string `json:"teamName"`
+	URL  string `json:"teamApisUrl"`
 }
 
 // ApiaryApisResponse is a struct of answer to GetApis() all
@@ -44,6 +79,16 @@ type ApiaryApisResponse struct {
 	Apis []ApiaryApiResponse `json:"apis"`
 }
 
+// TeamApisResult is a struct of answer to GetTeamApisResult() call
+//
+// Description:
+// Team - team APIs were requested for
+// Apis - team APIs
+type TeamApisResult struct {
+	Team string
+	ApiaryApisResponse
+}
+
 // ApiaryApiResponse is a helper struct of API response: GetApis(), GetTeamApis()
 //
 // Description:
@@ -54,14 +99,74 @@ type ApiaryApisResponse struct {
 // Public - is this doc public
 // Team - is this doc belongs to team
 // Personal - this this doc personal
+// UpdatedAt - time of last doc update, nil when API doesn't provide it
 type ApiaryApiResponse struct {
-	Name             string `json:"apiName"`
-	DocumentationURL string `json:"apiDocumentationUrl"`
-	Subdomain        string `json:"apiSubdomain"`
-	Private          bool   `json:"apiIsPrivate"`
-	Public           bool   `json:"apiIsPublic"`
-	Team             bool   `json:"apiIsTeam"`
-	Personal         bool   `json:"apiIsPersonal"`
+	Name             string     `json:"apiName"`
+	DocumentationURL string     `json:"apiDocumentationUrl"`
+	Subdomain        string     `json:"apiSubdomain"`
+	Private          bool       `json:"apiIsPrivate"`
+	Public           bool       `json:"apiIsPublic"`
+	Team             bool       `json:"apiIsTeam"`
+	Personal         bool       `json:"apiIsPersonal"`
+	UpdatedAt        *time.Time `json:"apiUpdatedAt,omitempty"`
+}
+
+// UnmarshalJSON decodes API accepting both camelCase (apiName) and
+// snake_case (api_name) keys, as different API versions use either
+func (r *ApiaryApiResponse) UnmarshalJSON(data []byte) error {
+	type camelCase ApiaryApiResponse
+	var api camelCase
+	if err := json.Unmarshal(data, &api); err != nil {
+		return err
+	}
+
+	var snakeCase struct {
+		Name             string     `json:"api_name"`
+		DocumentationURL string     `json:"api_documentation_url"`
+		Subdomain        string     `json:"api_subdomain"`
+		Private          bool       `json:"api_is_private"`
+		Public           bool       `json:"api_is_public"`
+		Team             bool       `json:"api_is_team"`
+		Personal         bool       `json:"api_is_personal"`
+		UpdatedAt        *time.Time `json:"api_updated_at"`
+	}
+	if err := json.Unmarshal(data, &snakeCase); err != nil {
+		return err
+	}
+
+	if api.Name == "" {
+		api.Name = snakeCase.Name
+	}
+
+	if api.DocumentationURL == "" {
+		api.DocumentationURL = snakeCase.DocumentationURL
+	}
+
+	if api.Subdomain == "" {
+		api.Subdomain = snakeCase.Subdomain
+	}
+
+	if api.UpdatedAt == nil {
+		api.UpdatedAt = snakeCase.UpdatedAt
+	}
+
+	api.Private = api.Private || snakeCase.Private
+	api.Public = api.Public || snakeCase.Public
+	api.Team = api.Team || snakeCase.Team
+	api.Personal = api.Personal || snakeCase.Personal
+
+	*r = ApiaryApiResponse(api)
+	return nil
+}
+
+// ApiWithTime is a struct of API in answer to GetApisWithTimestamps() call
+//
+// Description:
+// Api - API as listed by GetApis()
+// UpdatedAt - time of last API update, nil when it is unknown
+type ApiWithTime struct {
+	Api       ApiaryApiResponse
+	UpdatedAt *time.Time
 }
 
 // ApiaryFetchResponse is a struct of Fetch response
@@ -76,7 +181,9 @@ type ApiaryFetchResponse struct {
 	Code    string `json:"code"`
 }
 
-// ApiaryInterface this interface is primary need for testing purposes
+// ApiaryInterface this interface is primary need for testing purposes. It
+// is kept to the core calls, so existing implementations keep compiling,
+// other calls are available on *Apiary returned by New().
 type ApiaryInterface interface {
 	Me() (me ApiaryMeResponse, err error)
 	GetApis() (apis *ApiaryApisResponse, err error)
@@ -85,6 +192,8 @@ type ApiaryInterface interface {
 	FetchBlueprint(name string) (blueprint *ApiaryFetchResponse, err error)
 }
 
+var _ ApiaryInterface = (*Apiary)(nil)
+
 // Apiary basic API client
 //
 // Usage:
@@ -115,21 +224,170 @@ type ApiaryInterface interface {
 //	fmt.Printf("URL: %s\n", response.URL)
 //}
 type Apiary struct {
-	options ApiaryOptions
-	client  *http.Client
+	options   ApiaryOptions
+	client    *http.Client
+	transport *http.Transport
+	cache     ResponseCache
+	flights   *flightGroup
+	slots     chan struct{}
+
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+	token    string
+	last     *ResponseInfo
+
+	userMu sync.Mutex
+	user   *ApiaryMeResponse
+}
+
+// Logger is an interface of logger used by client, *log.Logger implements it
+type Logger interface {
+	Printf(format string, v ...interface{})
 }
 
 // ApiaryOptions structure of possible API options
 // Token - Your apiary.io token's to access API.
+// Tokens - Backup tokens tried in order when previous one is rejected with
+// 401/403.
+// CredentialStore - Store token is read from on first request when Token is
+// empty.
+// Logger - Logger for client events, nothing is logged when nil.
+// ReadOnly - Make publishing and other mutating methods fail with ErrReadOnly
+// without making any request.
+// PolicyHook - Called with method, path and body of every mutating request
+// before it is sent, returned error aborts the call. Body is passed as it is
+// sent (compressed with CompressPublish), nil for streamed publish.
+// NormalizeLineEndings - Convert CRLF line endings to LF in blueprint content
+// before publishing. Note that this modifies the content which is sent.
+// SkipUnchanged - Fetch current blueprint before publish and skip publishing
+// when content is the same (line endings normalized), so no needless
+// revision is created. Publish is not skipped when blueprint can't be fetched.
+// MaxPublishBytes - Maximum size of blueprint content PublishBlueprint would
+// send, DefaultMaxPublishBytes when zero.
+// CompressPublish - Send publish request body gzip compressed, use only with
+// servers accepting Content-Encoding: gzip requests.
+// ProbeDocsURL - Check that docs page is reachable in PublishAndGetDocsURL.
+// Headers - Default headers sent with every request of endpoint class, auth
+// headers of a class can't be overridden.
+// BlueprintAuthOrder - Auth schemes blueprint fetches try in order while
+// apiary.io answers with 401, DefaultBlueprintAuthOrder when empty.
+// DefaultTeam - Name or ID of team DefaultTeam() returns, first user team
+// when empty.
+// RetryPolicy - Retry options of failed requests, requests are not retried by
+// default.
+// BaseURL - URL of apiary.io API, ApiaryAPIURL when empty.
+// Timeout - Overall timeout of a request, including reading response.
+// DialTimeout - Timeout of connection setup.
+// Middlewares - Wrappers of client transport applied in order, first one is
+// outermost.
+// RequestInterceptor - Called with every built request to apiary.io (or
+// BaseURL) host before it is sent, may modify it. Returned error aborts the
+// request.
+// RequestSigner - Called with every built request to apiary.io (or BaseURL)
+// host to sign it for gateway in front of apiary.io, returned header is set on
+// request. Independent of token auth, returned error aborts the request.
+// FieldNames - JSON field names used by proxy in front of apiary.io, by
+// apiary.io field name (like "userId": "id"). Fields of user and API list
+// responses are renamed back while decoding, raw and blueprint bodies are
+// left as is.
+// UserAgent - User-Agent header of requests, Go default when empty.
+// MaxConcurrent - Maximum number of requests in flight at once across all
+// calls, requests over limit wait for a free slot. Not limited when zero.
+// Parallelism - Maximum number of concurrent requests of batch calls,
+// DefaultParallelism when zero.
+// ConditionalRequests - Cache API lists and request them with
+// If-Modified-Since, so unchanged lists are not downloaded again.
+// Cache - Cache of ConditionalRequests responses, may be shared between
+// clients. In-memory cache of client is used when nil.
+// CoalesceRequests - Share one round trip between concurrent identical GET
+// requests.
+// Trace - Called with phase timings (DNS, connect, TLS, time to first byte)
+// of every request, for debugging slow calls.
+// Operations - OperationManager every request is tracked with, so it can
+// be canceled by ID.
+// Converter - Converter used by ConvertToOpenAPI and FetchOpenAPI.
+// ParserURL - URL of API Blueprint parser service blueprints are validated
+// with, like ApiBlueprintParserURL. Blueprint content is sent to the service,
+// so validation fails with ErrNoParser unless it is set.
+// Tracer - Tracer starting span of every request, with action, HTTP method,
+// status code and error recorded.
+// DumpResponsesDir - Directory every response body is written to, in
+// timestamped file named after API action. Nothing is written when empty.
+// Clock - Source of time for delays and timestamps, real time when nil.
+// HTTPClient - Client used to make requests. When set Timeout, DialTimeout,
+// Middlewares and RetryPolicy.ProxySelector are not applied, configure client
+// instead.
 type ApiaryOptions struct {
-	Token string
+	Token                string
+	Tokens               []string
+	CredentialStore      CredentialStore
+	Logger               Logger
+	ReadOnly             bool
+	PolicyHook           func(method string, path string, body []byte) error
+	NormalizeLineEndings bool
+	SkipUnchanged        bool
+	MaxPublishBytes      int64
+	CompressPublish      bool
+	ProbeDocsURL         bool
+	Headers              map[EndpointClass]map[string]string
+	BlueprintAuthOrder   []EndpointClass
+	DefaultTeam          string
+	RetryPolicy          RetryPolicy
+	BaseURL              string
+	Timeout              time.Duration
+	DialTimeout          time.Duration
+	Middlewares          []Middleware
+	RequestInterceptor   func(*http.Request) error
+	RequestSigner        RequestSigner
+	FieldNames           map[string]string
+	UserAgent            string
+	MaxConcurrent        int
+	Parallelism          int
+	ConditionalRequests  bool
+	Cache                ResponseCache
+	CoalesceRequests     bool
+	Trace                func(path string, timing TraceTiming)
+	Operations           *OperationManager
+	Converter            Converter
+	ParserURL            string
+	Tracer               Tracer
+	DumpResponsesDir     string
+	Clock                Clock
+	HTTPClient           *http.Client
 }
 
 // NewApiary create new Apiary.io client
 func NewApiary(opts ApiaryOptions) ApiaryInterface {
+	return New(opts)
+}
+
+// New create new Apiary.io client, unlike NewApiary it returns *Apiary with
+// all the calls available
+func New(opts ApiaryOptions) *Apiary {
+	client := opts.HTTPClient
+	var owned *http.Transport
+	if client == nil {
+		var transport http.RoundTripper
+		transport, owned = newTransport(opts)
+		client = &http.Client{
+			Transport: transport,
+			Timeout:   opts.Timeout,
+		}
+	}
+
+	var cache ResponseCache = newConditionalCache()
+	if opts.Cache != nil {
+		cache = opts.Cache
+	}
+
 	return &Apiary{
-		options: opts,
-		client:  &http.Client{},
+		options:   opts,
+		client:    client,
+		transport: owned,
+		cache:     cache,
+		flights:   newFlightGroup(),
+		slots:     newSlots(opts.MaxConcurrent),
 	}
 }
 
@@ -137,7 +395,17 @@ func NewApiary(opts ApiaryOptions) ApiaryInterface {
 //
 // Reference: http://docs.apiary.apiary.io/#reference/user-information/me/get-me
 func (a *Apiary) Me() (me ApiaryMeResponse, err error) {
-	data, response, err := a.sendRequest(apiaryActionMe)
+	return a.me(context.Background())
+}
+
+// MeWith retrieve user information as Me() does, applying per-call options
+func (a *Apiary) MeWith(options ...RequestOption) (me ApiaryMeResponse, err error) {
+	return a.me(withRequestOptions(context.Background(), options))
+}
+
+// me retrieve user information, canceling request when ctx is done
+func (a *Apiary) me(ctx context.Context) (me ApiaryMeResponse, err error) {
+	data, response, err := a.sendRequestContext(ctx, apiaryActionMe)
 	if err != nil {
 		return
 	}
@@ -147,7 +415,7 @@ func (a *Apiary) Me() (me ApiaryMeResponse, err error) {
 		return
 	}
 
-	err = json.Unmarshal(data, &me)
+	err = a.decode(data, &me)
 	if err != nil {
 		return
 	}
@@ -159,17 +427,46 @@ func (a *Apiary) Me() (me ApiaryMeResponse, err error) {
 //
 // Reference: http://docs.apiary.apiary.io/#reference/api-list/user-api-list/get-me
 func (a *Apiary) GetApis() (apis *ApiaryApisResponse, err error) {
-	data, response, err := a.sendRequest(apiaryActionGetApis)
+	return a.GetApisWith()
+}
+
+// GetApisWith return list of user blueprints/APIs as GetApis() does,
+// applying per-call options
+func (a *Apiary) GetApisWith(options ...RequestOption) (apis *ApiaryApisResponse, err error) {
+	apis, _, err = a.GetApisIfModifiedWith(options...)
+	return
+}
+
+// GetApisIfModified return list of user blueprints/APIs. With
+// ConditionalRequests option list is requested with If-Modified-Since and
+// cached list is returned with modified false when server responds with
+// 304 Not Modified.
+func (a *Apiary) GetApisIfModified() (apis *ApiaryApisResponse, modified bool, err error) {
+	return a.GetApisIfModifiedWith()
+}
+
+// GetApisIfModifiedWith return list of user blueprints/APIs as
+// GetApisIfModified() does, applying per-call options
+func (a *Apiary) GetApisIfModifiedWith(options ...RequestOption) (apis *ApiaryApisResponse, modified bool, err error) {
+	return a.listApis(withRequestOptions(context.Background(), options), apiaryActionGetApis)
+}
+
+// listApis return list of blueprints/APIs at path, canceling request when
+// ctx is done
+func (a *Apiary) listApis(ctx context.Context, path string) (apis *ApiaryApisResponse, modified bool, err error) {
+	data, response, modified, err := a.sendConditionalRequest(ctx, path)
 	if err != nil {
 		return
 	}
 
-	err = checkOk(response)
-	if err != nil {
-		return
+	if modified {
+		err = checkOk(response)
+		if err != nil {
+			return
+		}
 	}
 
-	err = json.Unmarshal(data, &apis)
+	err = a.decode(data, &apis)
 	if err != nil {
 		return
 	}
@@ -181,8 +478,28 @@ func (a *Apiary) GetApis() (apis *ApiaryApisResponse, err error) {
 //
 // Reference: http://docs.apiary.apiary.io/#reference/api-list/team-api-list/get-me
 func (a *Apiary) GetTeamApis(team string) (apis *ApiaryApisResponse, err error) {
+	return a.GetTeamApisWith(team)
+}
+
+// GetTeamApisWith return list of team blueprints/APIs as GetTeamApis() does,
+// applying per-call options
+func (a *Apiary) GetTeamApisWith(team string, options ...RequestOption) (apis *ApiaryApisResponse, err error) {
 	uri := fmt.Sprintf(apiaryActionGetTeamApis, team)
-	data, response, err := a.sendRequest(uri)
+	apis, _, err = a.listApis(withRequestOptions(context.Background(), options), uri)
+	return
+}
+
+// GetApisByURL return list of blueprints/APIs from absolute URL given by
+// API, like ApiaryTeam.URL. ErrForeignURL is returned, without making a
+// request, for URLs which are not on apiary.io (or BaseURL) host, so token
+// is never sent elsewhere.
+func (a *Apiary) GetApisByURL(fullURL string) (apis *ApiaryApisResponse, err error) {
+	if !a.trustedURL(fullURL) {
+		err = ErrForeignURL
+		return
+	}
+
+	data, response, err := a.sendRequest(fullURL)
 	if err != nil {
 		return
 	}
@@ -192,50 +509,211 @@ func (a *Apiary) GetTeamApis(team string) (apis *ApiaryApisResponse, err error)
 		return
 	}
 
-	err = json.Unmarshal(data, &apis)
+	err = a.decode(data, &apis)
+	return
+}
+
+// GetTeamApisResult return list of team blueprints/APIs along with the team
+func (a *Apiary) GetTeamApisResult(team string) (result *TeamApisResult, err error) {
+	apis, err := a.GetTeamApis(team)
 	if err != nil {
 		return
 	}
 
+	result = &TeamApisResult{
+		Team:               team,
+		ApiaryApisResponse: *apis,
+	}
+
+	return
+}
+
+// GetApisChangedSince return user blueprints/APIs updated at or after since
+// (inclusive). apiary.io has no query for it, so APIs are filtered client-side
+// by update time from GetApisWithTimestamps(), which is set to UpdatedAt of
+// returned APIs. APIs with unknown update time are returned as they may have
+// changed, along with ApiErrors when their blueprints couldn't be fetched.
+func (a *Apiary) GetApisChangedSince(since time.Time) (apis []ApiaryApiResponse, err error) {
+	all, err := a.GetApisWithTimestamps()
+	if _, partial := err.(ApiErrors); err != nil && !partial {
+		return
+	}
+
+	apis = []ApiaryApiResponse{}
+	for _, api := range all {
+		if api.UpdatedAt == nil || !api.UpdatedAt.Before(since) {
+			api.Api.UpdatedAt = api.UpdatedAt
+			apis = append(apis, api.Api)
+		}
+	}
+
+	return
+}
+
+// GetApisWithTimestamps return user blueprints/APIs with time of their last
+// update. APIs listed without apiUpdatedAt have it taken from Last-Modified
+// header of their blueprint, fetched concurrently, no more than Parallelism
+// at once. APIs which blueprints couldn't be fetched are returned along with
+// ApiErrors.
+func (a *Apiary) GetApisWithTimestamps() (apis []ApiWithTime, err error) {
+	all, err := a.GetApis()
+	if err != nil {
+		return
+	}
+
+	apis = make([]ApiWithTime, len(all.Apis))
+	errs := make(ApiErrors)
+	var mu sync.Mutex
+
+	a.parallel(len(all.Apis), func(i int) {
+		api := all.Apis[i]
+		apis[i] = ApiWithTime{
+			Api:       api,
+			UpdatedAt: api.UpdatedAt,
+		}
+
+		if api.UpdatedAt != nil {
+			return
+		}
+
+		modified, apiErr := a.blueprintLastModified(api.Subdomain)
+		if apiErr != nil {
+			mu.Lock()
+			errs[api.Subdomain] = apiErr
+			mu.Unlock()
+			return
+		}
+
+		apis[i].UpdatedAt = modified
+	})
+
+	if len(errs) > 0 {
+		err = errs
+	}
+
+	return
+}
+
+// blueprintLastModified return time from Last-Modified header of blueprint,
+// nil when there is none
+func (a *Apiary) blueprintLastModified(name string) (modified *time.Time, err error) {
+	_, response, err := a.sendBlueprintRequest(context.Background(), fmt.Sprintf(apiaryActionFetchBlueprint, name), nil)
+	if err != nil {
+		return
+	}
+
+	err = checkOk(response)
+	if err != nil {
+		return
+	}
+
+	t, parseErr := http.ParseTime(response.Header.Get("Last-Modified"))
+	if parseErr == nil {
+		modified = &t
+	}
+
 	return
 }
 
-// PublishBlueprint publish blueprint in Apiary.io
+// GetPersonalApis return list of user personal blueprints/APIs
 //
-// Reference: http://docs.apiary.apiary.io/#reference/blueprint/publish-blueprint/get-me
-func (a *Apiary) PublishBlueprint(name string, content []byte) (published bool, err error) {
-	jsonData, err := json.Marshal(map[string]string{
-		"code": string(content),
+// apiary.io can't scope me/apis by owner, so APIs are filtered client-side.
+func (a *Apiary) GetPersonalApis() (apis *ApiaryApisResponse, err error) {
+	return a.filterApis(func(api ApiaryApiResponse) bool {
+		return api.Personal
+	})
+}
+
+// GetTeamOwnedApis return list of user blueprints/APIs owned by user teams
+//
+// apiary.io can't scope me/apis by owner, so APIs are filtered client-side.
+func (a *Apiary) GetTeamOwnedApis() (apis *ApiaryApisResponse, err error) {
+	return a.filterApis(func(api ApiaryApiResponse) bool {
+		return api.Team
 	})
+}
 
+// ListSubdomains return subdomains of all user blueprints/APIs in order they
+// returned by GetApis
+func (a *Apiary) ListSubdomains() (subdomains []string, err error) {
+	apis, err := a.GetApis()
 	if err != nil {
 		return
 	}
 
-	uri := fmt.Sprintf(apiaryActionPublishBlueprint, name)
-	data, response, err := a.sendLegacyPostRequest(uri, bytes.NewBuffer(jsonData))
+	subdomains = make([]string, 0, len(apis.Apis))
+	for _, api := range apis.Apis {
+		subdomains = append(subdomains, api.Subdomain)
+	}
+
+	return
+}
+
+// CanPublish check that current token owns API and can publish into it.
+// APIs which are personal or belong to user team are owned, others are
+// read-only. ErrApiNotFound returned for APIs token can't see.
+func (a *Apiary) CanPublish(subdomain string) (can bool, err error) {
+	apis, err := a.GetApis()
 	if err != nil {
 		return
 	}
 
-	if response.StatusCode != http.StatusCreated {
-		var apiaryError struct {
-			Error   bool   `json:"error"`
-			Message string `json:"message"`
+	for _, api := range apis.Apis {
+		if api.Subdomain == subdomain {
+			can = api.Personal || api.Team
+			return
 		}
+	}
 
-		err = json.Unmarshal(data, &apiaryError)
-		if err != nil {
-			return
+	err = ErrApiNotFound
+	return
+}
+
+// MockServerURL return base URL of apiary.io mock server of API. URL is
+// derived from subdomain, mock servers of private APIs have URLs with
+// a secret part and ErrMockUnavailable is returned for them.
+// ErrApiNotFound returned for APIs token can't see.
+func (a *Apiary) MockServerURL(subdomain string) (url string, err error) {
+	apis, err := a.GetApis()
+	if err != nil {
+		return
+	}
+
+	for _, api := range apis.Apis {
+		if api.Subdomain != subdomain {
+			continue
 		}
 
-		if apiaryError.Error {
-			err = fmt.Errorf("Creation failed: %s", apiaryError.Message)
+		if api.Private {
+			err = ErrMockUnavailable
 			return
 		}
+
+		url = fmt.Sprintf(apiaryMockURL, subdomain)
+		return
 	}
 
-	published = true
+	err = ErrApiNotFound
+	return
+}
+
+// PublishBlueprint publish blueprint in Apiary.io. Blueprint published with
+// parser warnings is reported with published true and *WarningError. With
+// SkipUnchanged option unchanged blueprint is reported with published false
+// and nil error.
+//
+// Reference: http://docs.apiary.apiary.io/#reference/blueprint/publish-blueprint/get-me
+func (a *Apiary) PublishBlueprint(name string, content []byte) (published bool, err error) {
+	return a.PublishBlueprintWith(name, content)
+}
+
+// PublishBlueprintWith publish blueprint as PublishBlueprint() does,
+// applying per-call options
+func (a *Apiary) PublishBlueprintWith(name string, content []byte, options ...RequestOption) (published bool, err error) {
+	result, err := a.publish(withRequestOptions(context.Background(), options), name, content, PublishOptions{}, nil)
+	if result != nil {
+		published = result.Published
+	}
 
 	return
 }
@@ -244,8 +722,14 @@ func (a *Apiary) PublishBlueprint(name string, content []byte) (published bool,
 //
 // Reference: Unknown
 func (a *Apiary) FetchBlueprint(name string) (blueprint *ApiaryFetchResponse, err error) {
+	return a.FetchBlueprintWith(name)
+}
+
+// FetchBlueprintWith fetches blueprint as FetchBlueprint() does, applying
+// per-call options
+func (a *Apiary) FetchBlueprintWith(name string, options ...RequestOption) (blueprint *ApiaryFetchResponse, err error) {
 	uri := fmt.Sprintf(apiaryActionFetchBlueprint, name)
-	data, response, err := a.sendLegacyRequest(uri)
+	data, response, err := a.sendBlueprintRequest(withRequestOptions(context.Background(), options), uri, nil)
 	if err != nil {
 		return
 	}
@@ -1,10 +1,15 @@
 package apiary
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/jarcoal/httpmock.v1"
 )
@@ -235,6 +240,290 @@ func TestApiary_GetApis(t *testing.T) {
 	})
 }
 
+func Test_ApiaryApiResponse_UnmarshalJSON(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		body string
+	}{
+		{"Decode camelCase keys", `{
+			"apiName": "API",
+			"apiDocumentationUrl": "https://api.docs.apiary.io/",
+			"apiSubdomain": "api",
+			"apiIsPrivate": true,
+			"apiIsTeam": true,
+			"apiUpdatedAt": "2017-01-01T00:00:00Z"
+		}`},
+		{"Decode snake_case keys", `{
+			"api_name": "API",
+			"api_documentation_url": "https://api.docs.apiary.io/",
+			"api_subdomain": "api",
+			"api_is_private": true,
+			"api_is_team": true,
+			"api_updated_at": "2017-01-01T00:00:00Z"
+		}`},
+	} {
+		body := c.body
+		t.Run(c.name, func(t *testing.T) {
+			var api ApiaryApiResponse
+			err := json.Unmarshal([]byte(body), &api)
+			if err != nil {
+				t.Fatalf("Error: %s", err.Error())
+			}
+
+			if api.Name != "API" || api.DocumentationURL != "https://api.docs.apiary.io/" || api.Subdomain != "api" {
+				t.Errorf("Wrong API: %+v", api)
+			}
+
+			if !api.Private || api.Public || !api.Team || api.Personal {
+				t.Errorf("Wrong API flags: %+v", api)
+			}
+
+			if api.UpdatedAt == nil || !api.UpdatedAt.Equal(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("Wrong UpdatedAt: %v", api.UpdatedAt)
+			}
+		})
+	}
+}
+
+func TestApiary_ScopedApis(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responder := httpmock.NewStringResponder(200, `{"apis": [
+		{"apiSubdomain": "personal", "apiIsPersonal": true},
+		{"apiSubdomain": "team", "apiIsTeam": true},
+		{"apiSubdomain": "public", "apiIsPublic": true},
+		{"apiSubdomain": "personal2", "apiIsPersonal": true}
+	]}`)
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionGetApis, responder)
+
+	a := New(ApiaryOptions{
+		Token: Token,
+	})
+
+	subdomains := func(apis *ApiaryApisResponse) string {
+		names := []string{}
+		for _, api := range apis.Apis {
+			names = append(names, api.Subdomain)
+		}
+
+		return strings.Join(names, ",")
+	}
+
+	t.Run("GetPersonalApis()", func(t *testing.T) {
+		r, err := a.GetPersonalApis()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if subdomains(r) != "personal,personal2" {
+			t.Errorf("Wrong personal apis: %s", subdomains(r))
+		}
+	})
+
+	t.Run("GetTeamOwnedApis()", func(t *testing.T) {
+		r, err := a.GetTeamOwnedApis()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if subdomains(r) != "team" {
+			t.Errorf("Wrong team apis: %s", subdomains(r))
+		}
+	})
+}
+
+func TestApiary_GetApisChangedSince(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responder := httpmock.NewStringResponder(200, `{"apis": [
+		{"apiSubdomain": "old", "apiUpdatedAt": "2017-01-01T00:00:00Z"},
+		{"apiSubdomain": "exact", "apiUpdatedAt": "2017-02-01T00:00:00Z"},
+		{"apiSubdomain": "new", "apiUpdatedAt": "2017-03-01T10:00:00+02:00"},
+		{"apiSubdomain": "old-header"},
+		{"apiSubdomain": "new-header"},
+		{"apiSubdomain": "unknown"},
+		{"apiSubdomain": "failed"}
+	]}`)
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionGetApis, responder)
+
+	lastModified := func(modified string) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			response := httpmock.NewStringResponse(200, `{"code": ""}`)
+			response.Header = http.Header{}
+			response.Header.Set("Last-Modified", modified)
+			return response, nil
+		}
+	}
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, "old-header"), lastModified("Sun, 01 Jan 2017 00:00:00 GMT"))
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, "new-header"), lastModified("Wed, 01 Mar 2017 00:00:00 GMT"))
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, "unknown"), httpmock.NewStringResponder(200, `{"code": ""}`))
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, "failed"), httpmock.NewStringResponder(500, `{}`))
+
+	a := New(ApiaryOptions{
+		Token: Token,
+	})
+
+	r, err := a.GetApisChangedSince(time.Date(2017, 2, 1, 0, 0, 0, 0, time.UTC))
+	errs, partial := err.(ApiErrors)
+	if !partial || len(errs) != 1 || errs["failed"] == nil {
+		t.Fatalf("Wrong error: %v", err)
+	}
+
+	names := []string{}
+	for _, api := range r {
+		names = append(names, api.Subdomain)
+	}
+
+	if strings.Join(names, ",") != "exact,new,new-header,unknown,failed" {
+		t.Errorf("Wrong changed apis: %v", names)
+	}
+
+	if r[2].UpdatedAt == nil || !r[2].UpdatedAt.Equal(time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Update time should be taken from Last-Modified: %v", r[2].UpdatedAt)
+	}
+}
+
+func TestApiary_ListSubdomains(t *testing.T) {
+	t.Run("Retrieve subdomains", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		responder := httpmock.NewStringResponder(200, `{"apis": [
+			{"apiName": "Zeta", "apiSubdomain": "zeta"},
+			{"apiName": "Alpha", "apiSubdomain": "alpha"},
+			{"apiName": "Mu", "apiSubdomain": "mu"}
+		]}`)
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionGetApis, responder)
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.ListSubdomains()
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if strings.Join(r, ",") != "zeta,alpha,mu" {
+			t.Errorf("Wrong subdomains returned: %v", r)
+		}
+	})
+
+	t.Run("Return error on request error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(401, "{}"))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.ListSubdomains()
+		if err == nil {
+			t.Error("Should return Error")
+		}
+	})
+}
+
+func TestApiary_CanPublish(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responder := httpmock.NewStringResponder(200, `{"apis": [
+		{"apiSubdomain": "personal", "apiIsPersonal": true},
+		{"apiSubdomain": "team", "apiIsTeam": true},
+		{"apiSubdomain": "public", "apiIsPublic": true}
+	]}`)
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionGetApis, responder)
+
+	a := New(ApiaryOptions{
+		Token: Token,
+	})
+
+	t.Run("Owned API", func(t *testing.T) {
+		for _, name := range []string{"personal", "team"} {
+			can, err := a.CanPublish(name)
+
+			if !can {
+				t.Errorf("Should be able to publish into %s", name)
+			}
+
+			if err != nil {
+				t.Errorf("Error: %s", err.Error())
+			}
+		}
+	})
+
+	t.Run("Read-only API", func(t *testing.T) {
+		can, err := a.CanPublish("public")
+
+		if can {
+			t.Error("Should not be able to publish into read-only API")
+		}
+
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+	})
+
+	t.Run("Unknown API", func(t *testing.T) {
+		can, err := a.CanPublish("unknown")
+
+		if can {
+			t.Error("Should not be able to publish into unknown API")
+		}
+
+		if err != ErrApiNotFound {
+			t.Error("Unknown API should return ErrApiNotFound")
+		}
+	})
+}
+
+func TestApiary_MockServerURL(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responder := httpmock.NewStringResponder(200, `{"apis": [
+		{"apiSubdomain": "public", "apiIsPublic": true},
+		{"apiSubdomain": "private", "apiIsPrivate": true}
+	]}`)
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionGetApis, responder)
+
+	a := New(ApiaryOptions{
+		Token: Token,
+	})
+
+	t.Run("Derive mock URL", func(t *testing.T) {
+		url, err := a.MockServerURL("public")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if url != "https://public.apiary-mock.com/" {
+			t.Errorf("Wrong mock URL: %s", url)
+		}
+	})
+
+	t.Run("Private API", func(t *testing.T) {
+		_, err := a.MockServerURL("private")
+
+		if err != ErrMockUnavailable {
+			t.Error("Private API should return ErrMockUnavailable")
+		}
+	})
+
+	t.Run("Unknown API", func(t *testing.T) {
+		_, err := a.MockServerURL("unknown")
+
+		if err != ErrApiNotFound {
+			t.Error("Unknown API should return ErrApiNotFound")
+		}
+	})
+}
+
 func TestApiary_GetTeamApis(t *testing.T) {
 	t.Run("Get invalid team", func(t *testing.T) {
 		a := NewApiary(ApiaryOptions{
@@ -282,6 +571,49 @@ func TestApiary_GetTeamApis(t *testing.T) {
 	})
 }
 
+func TestApiary_GetTeamApisResult(t *testing.T) {
+	t.Run("Populate team name", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		responder := httpmock.NewStringResponder(200, `{"apis": [{"apiSubdomain": "team"}]}`)
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionGetTeamApis, "team-id"), responder)
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.GetTeamApisResult("team-id")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if r.Team != "team-id" {
+			t.Errorf("Wrong team: %s", r.Team)
+		}
+
+		if len(r.Apis) != 1 || r.Apis[0].Subdomain != "team" {
+			t.Error("Wrong team apis")
+		}
+	})
+
+	t.Run("Return error on request error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(404, "{}"))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.GetTeamApisResult("team-id")
+		if err == nil || r != nil {
+			t.Error("Should return Error")
+		}
+	})
+}
+
 func TestApiary_FetchBlueprint(t *testing.T) {
 	t.Run("Fetching blueprint", func(t *testing.T) {
 		a := NewApiary(ApiaryOptions{
@@ -410,4 +742,236 @@ func TestApiary_PublishBlueprint(t *testing.T) {
 			t.Error("Wrong token should generate error")
 		}
 	})
+
+	t.Run("Publish CRLF content with normalization", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var sent struct {
+			Code string `json:"code"`
+		}
+
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := json.Unmarshal(body, &sent); err != nil {
+				return nil, err
+			}
+
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:                Token,
+			NormalizeLineEndings: true,
+		})
+
+		content := strings.Replace(string(ValidBlueprint), "\n", "\r\n", -1)
+		publish, err := a.PublishBlueprint(Repository, []byte(content))
+
+		if !publish {
+			t.Error("Not published")
+		}
+
+		if err != nil {
+			t.Errorf("Error: %s", err)
+		}
+
+		if sent.Code != string(ValidBlueprint) {
+			t.Error("CRLF content should be sent as LF")
+		}
+	})
+
+	t.Run("Publish oversized content", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:           Token,
+			MaxPublishBytes: 16,
+		})
+
+		publish, err := a.PublishBlueprint(Repository, ValidBlueprint)
+
+		if publish {
+			t.Error("Published")
+		}
+
+		if err != ErrBlueprintTooLarge {
+			t.Error("Oversized content should return ErrBlueprintTooLarge")
+		}
+
+		if requests != 0 {
+			t.Error("Oversized content should not be sent")
+		}
+	})
+}
+
+func TestApiary_GetApisWithTimestamps(t *testing.T) {
+	t.Run("Augment APIs with update time", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionGetApis, httpmock.NewStringResponder(200, `{"apis": [
+			{"apiSubdomain": "listed", "apiUpdatedAt": "2017-01-01T00:00:00Z"},
+			{"apiSubdomain": "header"},
+			{"apiSubdomain": "unknown"}
+		]}`))
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, "header"), func(req *http.Request) (*http.Response, error) {
+			response := httpmock.NewStringResponse(200, `{"code": ""}`)
+			response.Header = http.Header{}
+			response.Header.Set("Last-Modified", "Wed, 01 Feb 2017 00:00:00 GMT")
+			return response, nil
+		})
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, "unknown"), httpmock.NewStringResponder(200, `{"code": ""}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		apis, err := a.GetApisWithTimestamps()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(apis) != 3 {
+			t.Fatalf("Expected 3 APIs, got %d", len(apis))
+		}
+
+		if apis[0].UpdatedAt == nil || !apis[0].UpdatedAt.Equal(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Listed update time should be kept: %v", apis[0].UpdatedAt)
+		}
+
+		if apis[1].UpdatedAt == nil || !apis[1].UpdatedAt.Equal(time.Date(2017, 2, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Update time should be taken from Last-Modified: %v", apis[1].UpdatedAt)
+		}
+
+		if apis[2].UpdatedAt != nil || apis[2].Api.Subdomain != "unknown" {
+			t.Errorf("API without time should be kept without it: %+v", apis[2])
+		}
+	})
+}
+
+func TestApiary_GetApisByURL(t *testing.T) {
+	t.Run("Fetch APIs from URL given by API", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var auth string
+		httpmock.RegisterResponder("GET", "https://api.apiary.io/me/teams/t1/apis", func(req *http.Request) (*http.Response, error) {
+			auth = req.Header.Get("Authorization")
+			return httpmock.NewStringResponse(200, `{"apis": [{"apiSubdomain": "first"}]}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: "token",
+		})
+
+		apis, err := a.GetApisByURL("https://api.apiary.io/me/teams/t1/apis")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(apis.Apis) != 1 || apis.Apis[0].Subdomain != "first" {
+			t.Errorf("Wrong apis: %+v", apis.Apis)
+		}
+
+		if auth != "bearer token" {
+			t.Errorf("Request should be authorized, got: %s", auth)
+		}
+	})
+
+	t.Run("Reject off-host URLs", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, `{"apis": []}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		for _, u := range []string{
+			"https://evil.example.com/me/apis",
+			"https://apiary.io.evil.example.com/me/apis",
+			"http://api.apiary.io/me/apis",
+			"me/apis",
+		} {
+			_, err := a.GetApisByURL(u)
+			if err != ErrForeignURL {
+				t.Errorf("Expected ErrForeignURL for %s, got: %v", u, err)
+			}
+		}
+
+		if requests != 0 {
+			t.Error("Token should not be sent off host")
+		}
+	})
+}
+
+func TestApiary_BlueprintAuthOrder(t *testing.T) {
+	fetch := func(order []EndpointClass) (requests []string, err error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Authentication") != "" {
+				requests = append(requests, "legacy")
+				return httpmock.NewStringResponse(401, `{}`), nil
+			}
+
+			requests = append(requests, "bearer")
+			return httpmock.NewStringResponse(200, `{"code": "FORMAT: 1A"}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:              "token",
+			BlueprintAuthOrder: order,
+		})
+
+		_, err = a.FetchBlueprint(Repository)
+		return
+	}
+
+	t.Run("Fall back to bearer auth on 401", func(t *testing.T) {
+		requests, err := fetch(nil)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if strings.Join(requests, ",") != "legacy,bearer" {
+			t.Errorf("Wrong auth order: %v", requests)
+		}
+	})
+
+	t.Run("Use configured order", func(t *testing.T) {
+		requests, err := fetch([]EndpointClass{EndpointModern, EndpointLegacy})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if strings.Join(requests, ",") != "bearer" {
+			t.Errorf("Wrong auth order: %v", requests)
+		}
+	})
+
+	t.Run("Fail when every scheme is rejected", func(t *testing.T) {
+		_, err := fetch([]EndpointClass{EndpointLegacy})
+		if err == nil {
+			t.Error("Should return Error")
+		}
+	})
 }
@@ -0,0 +1,148 @@
+package apiary
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ProgressStage is a stage of blueprint publish reported by
+// PublishBlueprintsWithProgress()
+type ProgressStage string
+
+// Publish stages, every blueprint ends with either ProgressDone or
+// ProgressFailed
+const (
+	ProgressStarted   ProgressStage = "started"
+	ProgressValidated ProgressStage = "validated"
+	ProgressUploaded  ProgressStage = "uploaded"
+	ProgressDone      ProgressStage = "done"
+	ProgressFailed    ProgressStage = "failed"
+)
+
+// ProgressEvent is a struct of event sent by PublishBlueprintsWithProgress()
+//
+// Description:
+// Name - API name
+// Stage - publish stage reached
+// Err - publish error, only with ProgressFailed stage
+type ProgressEvent struct {
+	Name  string
+	Stage ProgressStage
+	Err   error
+}
+
+// BatchResult is a struct of answer to PublishBlueprints() call
+//
+// Description:
+// Succeeded - sorted names of published APIs, including ones published with
+// parser warnings
+// Warnings - *WarningError of APIs published with parser warnings, by name
+// Failed - publish errors of APIs which failed, by name. APIs in flight when
+// ctx is done fail with ctx error.
+// NotAttempted - sorted names of APIs not published as ctx was done before
+// their publish started
+type BatchResult struct {
+	Succeeded    []string
+	Warnings     map[string]*WarningError
+	Failed       map[string]error
+	NotAttempted []string
+}
+
+// PublishBlueprints publish blueprints by API name concurrently, no more
+// than Parallelism at once, and return result of every API. When ctx is done
+// requests in flight are canceled and APIs not published yet are reported as
+// not attempted, while already published ones keep their results.
+func (a *Apiary) PublishBlueprints(ctx context.Context, blueprints map[string][]byte) *BatchResult {
+	return a.publishBatch(ctx, blueprints, nil)
+}
+
+// PublishBlueprintsWithDeadline is PublishBlueprints() which whole batch must
+// finish before deadline
+func (a *Apiary) PublishBlueprintsWithDeadline(blueprints map[string][]byte, deadline time.Time) *BatchResult {
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	return a.PublishBlueprints(ctx, blueprints)
+}
+
+// PublishBlueprintsWithProgress is PublishBlueprints() reporting stages of
+// every blueprint publish. Channel is buffered to hold all events of batch
+// and closed when batch completes.
+func (a *Apiary) PublishBlueprintsWithProgress(ctx context.Context, blueprints map[string][]byte) <-chan ProgressEvent {
+	events := make(chan ProgressEvent, 4*len(blueprints))
+
+	go func() {
+		defer close(events)
+
+		a.publishBatch(ctx, blueprints, func(event ProgressEvent) {
+			events <- event
+		})
+	}()
+
+	return events
+}
+
+// publishBatch publish blueprints reporting progress events when progress
+// is set
+func (a *Apiary) publishBatch(ctx context.Context, blueprints map[string][]byte, progress func(event ProgressEvent)) *BatchResult {
+	if progress == nil {
+		progress = func(ProgressEvent) {}
+	}
+
+	names := make([]string, 0, len(blueprints))
+	for name := range blueprints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &BatchResult{
+		Succeeded:    []string{},
+		Warnings:     make(map[string]*WarningError),
+		Failed:       make(map[string]error),
+		NotAttempted: []string{},
+	}
+	var mu sync.Mutex
+
+	a.parallel(len(names), func(i int) {
+		name := names[i]
+		progress(ProgressEvent{Name: name, Stage: ProgressStarted})
+
+		err := ctx.Err()
+		attempted := err == nil
+		if attempted {
+			_, err = a.publish(ctx, name, blueprints[name], PublishOptions{}, func(stage ProgressStage) {
+				progress(ProgressEvent{Name: name, Stage: stage})
+			})
+		}
+
+		if err != nil {
+			progress(ProgressEvent{Name: name, Stage: ProgressFailed, Err: err})
+		} else {
+			progress(ProgressEvent{Name: name, Stage: ProgressDone})
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var warnings *WarningError
+		switch {
+		case !attempted:
+			result.NotAttempted = append(result.NotAttempted, name)
+		case err == nil:
+			result.Succeeded = append(result.Succeeded, name)
+		case errors.As(err, &warnings):
+			result.Succeeded = append(result.Succeeded, name)
+			result.Warnings[name] = warnings
+		default:
+			result.Failed[name] = err
+		}
+	})
+
+	sort.Strings(result.Succeeded)
+	sort.Strings(result.NotAttempted)
+
+	return result
+}
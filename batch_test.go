@@ -0,0 +1,149 @@
+package apiary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_PublishBlueprints(t *testing.T) {
+	t.Run("Publish every blueprint", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, "first"), httpmock.NewStringResponder(201, `{}`))
+		httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, "second"), httpmock.NewStringResponder(400, `{"error": true, "message": "Invalid"}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		result := a.PublishBlueprints(context.Background(), map[string][]byte{
+			"first":  ValidBlueprint,
+			"second": ValidBlueprint,
+		})
+
+		if len(result.Succeeded) != 1 || result.Succeeded[0] != "first" {
+			t.Errorf("First should be published, got: %v", result.Succeeded)
+		}
+
+		if result.Failed["second"] == nil {
+			t.Error("Second should fail")
+		}
+
+		if len(result.NotAttempted) != 0 {
+			t.Errorf("Every item should be attempted, got: %v", result.NotAttempted)
+		}
+	})
+
+	t.Run("Report warnings as succeeded", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(201, `{"warnings": [{"message": "Unused"}]}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		result := a.PublishBlueprints(context.Background(), map[string][]byte{
+			"first": ValidBlueprint,
+		})
+
+		if len(result.Succeeded) != 1 || len(result.Failed) != 0 {
+			t.Errorf("Blueprint with warnings should be published, got: %v", result)
+		}
+
+		if result.Warnings["first"] == nil {
+			t.Error("Warnings should be reported")
+		}
+	})
+
+	t.Run("Cut off items after deadline", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, "a"), httpmock.NewStringResponder(201, `{}`))
+		httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, "b"), httpmock.NewStringResponder(400, `{"error": true, "message": "Invalid"}`))
+		httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, "c"), func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		})
+		httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, "d"), httpmock.NewStringResponder(201, `{}`))
+		httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, "e"), httpmock.NewStringResponder(201, `{}`))
+
+		a := New(ApiaryOptions{
+			Token:       Token,
+			Parallelism: 1,
+		})
+
+		result := a.PublishBlueprintsWithDeadline(map[string][]byte{
+			"a": ValidBlueprint,
+			"b": ValidBlueprint,
+			"c": ValidBlueprint,
+			"d": ValidBlueprint,
+			"e": ValidBlueprint,
+		}, time.Now().Add(50*time.Millisecond))
+
+		if strings.Join(result.Succeeded, ",") != "a" {
+			t.Errorf("Item finished before deadline should keep result, got: %v", result.Succeeded)
+		}
+
+		if result.Failed["b"] == nil {
+			t.Error("Failed item should be reported")
+		}
+
+		if result.Failed["c"] == nil {
+			t.Error("Item in flight at deadline should fail")
+		}
+
+		if strings.Join(result.NotAttempted, ",") != "d,e" {
+			t.Errorf("Items after deadline should not be attempted, got: %v", result.NotAttempted)
+		}
+	})
+}
+
+func TestApiary_PublishBlueprintsWithProgress(t *testing.T) {
+	t.Run("Report stages of every blueprint", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, "first"), httpmock.NewStringResponder(201, `{}`))
+		httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, "second"), httpmock.NewStringResponder(400, `{"error": true, "message": "Invalid"}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		events := a.PublishBlueprintsWithProgress(context.Background(), map[string][]byte{
+			"first":  ValidBlueprint,
+			"second": ValidBlueprint,
+		})
+
+		stages := make(map[string][]string)
+		var failure error
+		for event := range events {
+			stages[event.Name] = append(stages[event.Name], string(event.Stage))
+			if event.Stage == ProgressFailed {
+				failure = event.Err
+			}
+		}
+
+		if s := strings.Join(stages["first"], ","); s != "started,validated,uploaded,done" {
+			t.Errorf("Wrong stages of first: %s", s)
+		}
+
+		if s := strings.Join(stages["second"], ","); s != "started,validated,uploaded,failed" {
+			t.Errorf("Wrong stages of second: %s", s)
+		}
+
+		if failure == nil {
+			t.Error("Failed event should carry error")
+		}
+	})
+}
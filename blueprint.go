@@ -0,0 +1,112 @@
+package apiary
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// BlueprintMetadata is a struct of metadata parsed from blueprint content
+//
+// Description:
+// Format - value of FORMAT metadata ("" when missing)
+// Host - value of HOST metadata ("" when missing)
+// Name - API name, first "# Title" heading
+type BlueprintMetadata struct {
+	Format string
+	Host   string
+	Name   string
+}
+
+// NormalizeBlueprint converts CRLF line endings in blueprint content to LF.
+// Windows-authored blueprints otherwise produce spurious revisions on publish.
+func NormalizeBlueprint(content []byte) []byte {
+	return bytes.Replace(content, []byte("\r\n"), []byte("\n"), -1)
+}
+
+// DecodedCode return blueprint text of Code. Code is unescaped when fetch
+// response JSON is decoded, so it is returned as is: escape sequences left
+// in it (like "\n" in JSON example or "\\" in regex) are blueprint text.
+func (f *ApiaryFetchResponse) DecodedCode() []byte {
+	return []byte(f.Code)
+}
+
+// ParseBlueprintMetadata extracts metadata and API name from API Blueprint
+// content without a network call. This is not a full parser: only metadata
+// lines before the first heading and the first "# Title" heading are read.
+func ParseBlueprintMetadata(content []byte) (*BlueprintMetadata, error) {
+	meta, err := parseMetadata(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if meta.Name == "" {
+		return nil, ErrNoApiName
+	}
+
+	return meta, nil
+}
+
+// BlueprintHost return API host URL declared with HOST metadata of API
+// Blueprint content, ErrNoHost returned when there is none
+func BlueprintHost(content []byte) (host string, err error) {
+	meta, err := parseMetadata(content)
+	if err != nil {
+		return
+	}
+
+	if meta.Host == "" {
+		err = ErrNoHost
+		return
+	}
+
+	return meta.Host, nil
+}
+
+// parseMetadata extracts metadata and API name from API Blueprint content
+func parseMetadata(content []byte) (*BlueprintMetadata, error) {
+	meta := &BlueprintMetadata{}
+	inMetadata := true
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			inMetadata = false
+
+			if strings.HasPrefix(line, "# ") {
+				meta.Name = strings.TrimSpace(line[2:])
+				break
+			}
+
+			continue
+		}
+
+		if !inMetadata {
+			continue
+		}
+
+		i := strings.Index(line, ":")
+		if i < 0 {
+			inMetadata = false
+			continue
+		}
+
+		switch strings.ToUpper(strings.TrimSpace(line[:i])) {
+		case "FORMAT":
+			meta.Format = strings.TrimSpace(line[i+1:])
+		case "HOST":
+			meta.Host = strings.TrimSpace(line[i+1:])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,133 @@
+package apiary
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_NormalizeBlueprint(t *testing.T) {
+	t.Run("Convert CRLF to LF", func(t *testing.T) {
+		r := NormalizeBlueprint([]byte("FORMAT: 1A\r\n# Hello\r\n\r\nText\n"))
+
+		if string(r) != "FORMAT: 1A\n# Hello\n\nText\n" {
+			t.Errorf("Wrong normalized content: %q", r)
+		}
+	})
+
+	t.Run("Leave LF content untouched", func(t *testing.T) {
+		r := NormalizeBlueprint(ValidBlueprint)
+
+		if string(r) != string(ValidBlueprint) {
+			t.Error("Content should not be changed")
+		}
+	})
+}
+
+func Test_ParseBlueprintMetadata(t *testing.T) {
+	t.Run("Parse valid blueprint", func(t *testing.T) {
+		meta, err := ParseBlueprintMetadata(ValidBlueprint)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if meta.Format != "1A" {
+			t.Errorf("Wrong format: %s", meta.Format)
+		}
+
+		if meta.Host != "" {
+			t.Errorf("Wrong host: %s", meta.Host)
+		}
+
+		if meta.Name != "Hello, world" {
+			t.Errorf("Wrong name: %s", meta.Name)
+		}
+	})
+
+	t.Run("Parse HOST", func(t *testing.T) {
+		meta, err := ParseBlueprintMetadata([]byte("FORMAT: 1A\nHOST: https://example.com/v1\n\n# Example\n"))
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if meta.Host != "https://example.com/v1" {
+			t.Errorf("Wrong host: %s", meta.Host)
+		}
+	})
+
+	t.Run("Parse blueprint without FORMAT", func(t *testing.T) {
+		meta, err := ParseBlueprintMetadata([]byte("# Example\n\n## Accounts [/accounts]\n"))
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if meta.Format != "" {
+			t.Errorf("Wrong format: %s", meta.Format)
+		}
+
+		if meta.Name != "Example" {
+			t.Errorf("Wrong name: %s", meta.Name)
+		}
+	})
+
+	t.Run("Return error on malformed blueprint", func(t *testing.T) {
+		_, err := ParseBlueprintMetadata([]byte("FORMAT: 1A\n\nsome invalid data\n## Accounts\n"))
+
+		if err != ErrNoApiName {
+			t.Error("Malformed blueprint should return ErrNoApiName")
+		}
+	})
+}
+
+func Test_BlueprintHost(t *testing.T) {
+	t.Run("Return declared host", func(t *testing.T) {
+		content := append([]byte("HOST: https://api.example.com/v1\n"), ValidBlueprint...)
+
+		host, err := BlueprintHost(content)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if host != "https://api.example.com/v1" {
+			t.Errorf("Wrong host: %s", host)
+		}
+	})
+
+	t.Run("Return error without HOST", func(t *testing.T) {
+		_, err := BlueprintHost(ValidBlueprint)
+		if err != ErrNoHost {
+			t.Errorf("Should return ErrNoHost, got: %v", err)
+		}
+	})
+
+	t.Run("Ignore HOST after first heading", func(t *testing.T) {
+		_, err := BlueprintHost([]byte("FORMAT: 1A\n# API\nHOST: https://api.example.com\n"))
+		if err != ErrNoHost {
+			t.Errorf("Should return ErrNoHost, got: %v", err)
+		}
+	})
+}
+
+func Test_DecodedCode(t *testing.T) {
+	t.Run("Keep code as decoded from JSON", func(t *testing.T) {
+		var f ApiaryFetchResponse
+		err := json.Unmarshal([]byte(`{"code": "# Caf\u00e9\n\n        {\"note\": \"a\\nb\", \"re\": \"\\\\d+\"}"}`), &f)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		expected := "# Café\n\n        {\"note\": \"a\\nb\", \"re\": \"\\\\d+\"}"
+		if string(f.DecodedCode()) != expected {
+			t.Errorf("Wrong decoded code: %q", f.DecodedCode())
+		}
+	})
+
+	t.Run("Keep unescaped code", func(t *testing.T) {
+		f := &ApiaryFetchResponse{
+			Code: string(ValidBlueprint),
+		}
+
+		if string(f.DecodedCode()) != string(ValidBlueprint) {
+			t.Error("Code without escapes should not be changed")
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package apiary
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// CachedResponse is a struct of response cached for conditional requests
+//
+// Description:
+// LastModified - Last-Modified header of response
+// Data - response body
+type CachedResponse struct {
+	LastModified string
+	Data         []byte
+}
+
+// ResponseCache is a cache of responses of conditional requests. It must be
+// safe for concurrent use, so it can be shared between clients. Keys are
+// distinct for different tokens and API URLs.
+type ResponseCache interface {
+	Get(key string) (entry CachedResponse, ok bool)
+	Set(key string, entry CachedResponse)
+}
+
+// conditionalCache is an in-memory ResponseCache
+type conditionalCache struct {
+	mu      sync.Mutex
+	entries map[string]CachedResponse
+}
+
+func newConditionalCache() *conditionalCache {
+	return &conditionalCache{
+		entries: make(map[string]CachedResponse),
+	}
+}
+
+func (c *conditionalCache) Get(key string) (entry CachedResponse, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok = c.entries[key]
+	return
+}
+
+func (c *conditionalCache) Set(key string, entry CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+}
+
+// cacheKey return key of response of path in ResponseCache, made of API URL
+// and hash of token, so clients with different tokens can share cache
+func (a *Apiary) cacheKey(path string) string {
+	var token string
+	if tokens, err := a.tokens(); err == nil {
+		token = tokens[0]
+	}
+
+	return fmt.Sprintf("%x %s%s", sha256.Sum256([]byte(token)), a.baseURL(), path)
+}
+
+// sendConditionalRequest makes GET request with If-Modified-Since of cached
+// response when ConditionalRequests option is set. On 304 Not Modified cached
+// data is returned with modified false.
+func (a *Apiary) sendConditionalRequest(ctx context.Context, path string) (data []byte, response *http.Response, modified bool, err error) {
+	if !a.options.ConditionalRequests {
+		data, response, err = a.sendRequestContext(ctx, path)
+		return data, response, true, err
+	}
+
+	headers := make(map[string]string)
+	key := a.cacheKey(path)
+	entry, cached := a.cache.Get(key)
+	if cached {
+		headers["If-Modified-Since"] = entry.LastModified
+	}
+
+	data, response, err = a.send(ctx, EndpointModern, "GET", path, headers, nil)
+	if cached && response != nil && response.StatusCode == http.StatusNotModified {
+		return entry.Data, response, false, nil
+	}
+
+	if err == nil && response.StatusCode == http.StatusOK {
+		if lastModified := response.Header.Get("Last-Modified"); lastModified != "" {
+			a.cache.Set(key, CachedResponse{
+				LastModified: lastModified,
+				Data:         data,
+			})
+		}
+	}
+
+	return data, response, true, err
+}
@@ -0,0 +1,184 @@
+package apiary
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_ConditionalRequests(t *testing.T) {
+	lastModified := "Wed, 21 Oct 2015 07:28:00 GMT"
+
+	mock := func(changed *bool, sent *[]string) {
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			*sent = append(*sent, req.Header.Get("If-Modified-Since"))
+			if req.Header.Get("If-Modified-Since") == lastModified && !*changed {
+				return httpmock.NewStringResponse(304, ``), nil
+			}
+
+			response := httpmock.NewStringResponse(200, `{"apis": [{"apiSubdomain": "example"}]}`)
+			response.Header.Set("Last-Modified", lastModified)
+			return response, nil
+		})
+	}
+
+	t.Run("Return cached list on 304", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		changed := false
+		var sent []string
+		mock(&changed, &sent)
+
+		a := New(ApiaryOptions{
+			Token:               Token,
+			ConditionalRequests: true,
+		})
+
+		_, modified, err := a.GetApisIfModified()
+		if err != nil || !modified {
+			t.Fatal("First request should be modified")
+		}
+
+		apis, modified, err := a.GetApisIfModified()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if modified {
+			t.Error("Second request should be a cache hit")
+		}
+
+		if len(apis.Apis) != 1 || apis.Apis[0].Subdomain != "example" {
+			t.Error("Cached list should be returned")
+		}
+
+		if len(sent) != 2 || sent[0] != "" || sent[1] != lastModified {
+			t.Errorf("Wrong If-Modified-Since sent: %v", sent)
+		}
+	})
+
+	t.Run("Return new list when modified", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		changed := true
+		var sent []string
+		mock(&changed, &sent)
+
+		a := New(ApiaryOptions{
+			Token:               Token,
+			ConditionalRequests: true,
+		})
+
+		a.GetApis()
+		apis, modified, err := a.GetApisIfModified()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !modified || len(apis.Apis) != 1 {
+			t.Error("Modified list should be returned")
+		}
+	})
+
+	t.Run("Do not send conditional headers by default", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		changed := false
+		var sent []string
+		mock(&changed, &sent)
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		a.GetApis()
+		_, modified, _ := a.GetApisIfModified()
+
+		if !modified || sent[1] != "" {
+			t.Error("Conditional requests should be disabled by default")
+		}
+	})
+}
+
+// sharedCache is a ResponseCache recording keys of stored responses
+type sharedCache struct {
+	mu      sync.Mutex
+	entries map[string]CachedResponse
+}
+
+func (c *sharedCache) Get(key string) (entry CachedResponse, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok = c.entries[key]
+	return
+}
+
+func (c *sharedCache) Set(key string, entry CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+}
+
+func TestApiary_SharedCache(t *testing.T) {
+	lastModified := "Wed, 21 Oct 2015 07:28:00 GMT"
+
+	t.Run("Share cached responses between clients", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var sent []string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req.Header.Get("If-Modified-Since"))
+			if req.Header.Get("If-Modified-Since") == lastModified {
+				return httpmock.NewStringResponse(304, ``), nil
+			}
+
+			response := httpmock.NewStringResponse(200, `{"apis": [{"apiSubdomain": "example"}]}`)
+			response.Header.Set("Last-Modified", lastModified)
+			return response, nil
+		})
+
+		cache := &sharedCache{entries: make(map[string]CachedResponse)}
+		client := func(token string) *Apiary {
+			return New(ApiaryOptions{
+				Token:               token,
+				ConditionalRequests: true,
+				Cache:               cache,
+			})
+		}
+
+		_, modified, err := client(Token).GetApisIfModified()
+		if err != nil || !modified {
+			t.Fatalf("First request should download list: %v", err)
+		}
+
+		apis, modified, err := client(Token).GetApisIfModified()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if modified || subdomains(apis.Apis) != "example" {
+			t.Error("Other client should get list from shared cache")
+		}
+
+		_, modified, err = client("other-token").GetApisIfModified()
+		if err != nil || !modified {
+			t.Errorf("Client with other token should not use cached list: %v", err)
+		}
+
+		if len(sent) != 3 || sent[0] != "" || sent[1] != lastModified || sent[2] != "" {
+			t.Errorf("Wrong If-Modified-Since headers: %q", sent)
+		}
+
+		if len(cache.entries) != 2 {
+			t.Errorf("Responses of both tokens should be cached, got %d", len(cache.entries))
+		}
+	})
+}
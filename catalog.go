@@ -0,0 +1,289 @@
+package apiary
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TeamErrors is an error of GetAllApis() call with errors of teams which
+// APIs couldn't be fetched, by team ID
+type TeamErrors map[string]error
+
+func (e TeamErrors) Error() string {
+	return joinErrors("Failed to fetch team APIs", e)
+}
+
+// joinErrors return message with errors sorted by key
+func joinErrors(prefix string, errs map[string]error) string {
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	messages := make([]string, 0, len(errs))
+	for _, key := range keys {
+		messages = append(messages, fmt.Sprintf("%s: %s", key, errs[key]))
+	}
+
+	return fmt.Sprintf("%s: %s", prefix, strings.Join(messages, "; "))
+}
+
+// ApiErrors is an error with errors of APIs which failed in a batch call,
+// by API subdomain
+type ApiErrors map[string]error
+
+func (e ApiErrors) Error() string {
+	return joinErrors("Failed APIs", e)
+}
+
+// PartialErrors is an error of batch call over all user APIs when both some
+// teams couldn't be listed and some APIs failed
+//
+// Description:
+// Teams - errors of teams which APIs couldn't be listed
+// Apis - errors of APIs which failed
+type PartialErrors struct {
+	Teams TeamErrors
+	Apis  ApiErrors
+}
+
+func (e *PartialErrors) Error() string {
+	return e.Teams.Error() + "; " + e.Apis.Error()
+}
+
+// GetAllApis return list of all user blueprints/APIs, personal and of every
+// user team, without duplicates. When some teams fail APIs of other teams
+// are returned along with TeamErrors.
+func (a *Apiary) GetAllApis() (apis *ApiaryApisResponse, err error) {
+	me, err := a.Me()
+	if err != nil {
+		return
+	}
+
+	apis, err = a.GetApis()
+	if err != nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, api := range apis.Apis {
+		seen[api.Subdomain] = true
+	}
+
+	errs := make(TeamErrors)
+	for _, team := range me.Teams {
+		teamApis, teamErr := a.GetTeamApis(team.ID)
+		if teamErr != nil {
+			errs[team.ID] = teamErr
+			continue
+		}
+
+		for _, api := range teamApis.Apis {
+			if !seen[api.Subdomain] {
+				seen[api.Subdomain] = true
+				apis.Apis = append(apis.Apis, api)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		err = errs
+	}
+
+	return
+}
+
+// CountApis return number of user blueprints/APIs. As apiary.io API has no
+// count endpoint whole list is downloaded, so counting costs as much as
+// GetApis(). With ConditionalRequests option unchanged list is not
+// downloaded again.
+func (a *Apiary) CountApis() (count int, err error) {
+	apis, err := a.GetApis()
+	if err != nil {
+		return
+	}
+
+	return len(apis.Apis), nil
+}
+
+// AllRepositoryNames return sorted subdomains of all APIs user can access,
+// personal and of every user team, without duplicates. As with GetAllApis()
+// names are returned along with TeamErrors when some teams fail.
+func (a *Apiary) AllRepositoryNames() (names []string, err error) {
+	apis, err := a.GetAllApis()
+	if _, partial := err.(TeamErrors); err != nil && !partial {
+		return
+	}
+
+	seen := make(map[string]bool)
+	names = []string{}
+	for _, api := range apis.Apis {
+		if !seen[api.Subdomain] {
+			seen[api.Subdomain] = true
+			names = append(names, api.Subdomain)
+		}
+	}
+	sort.Strings(names)
+
+	return
+}
+
+// PersonalGroup is a key of personal APIs in GroupApisByTeam() result
+const PersonalGroup = "personal"
+
+// GroupApisByTeam return user APIs grouped by name of owning team, personal
+// APIs are under PersonalGroup key. Teams with no APIs have empty list. As
+// with GetAllApis() groups are returned along with TeamErrors when some
+// teams fail. ErrDuplicateGroup is returned when team names don't identify
+// groups, because two teams have the same name or team is named as
+// PersonalGroup.
+func (a *Apiary) GroupApisByTeam() (groups map[string][]ApiaryApiResponse, err error) {
+	me, err := a.Me()
+	if err != nil {
+		return
+	}
+
+	names := map[string]bool{
+		PersonalGroup: true,
+	}
+	for _, team := range me.Teams {
+		if names[team.Name] {
+			err = ErrDuplicateGroup
+			return
+		}
+		names[team.Name] = true
+	}
+
+	personal, err := a.GetPersonalApis()
+	if err != nil {
+		return
+	}
+
+	groups = map[string][]ApiaryApiResponse{
+		PersonalGroup: personal.Apis,
+	}
+
+	errs := make(TeamErrors)
+	for _, team := range me.Teams {
+		teamApis, teamErr := a.GetTeamApis(team.ID)
+		if teamErr != nil {
+			errs[team.ID] = teamErr
+			continue
+		}
+
+		groups[team.Name] = append(make([]ApiaryApiResponse, 0, len(teamApis.Apis)), teamApis.Apis...)
+	}
+
+	if len(errs) > 0 {
+		err = errs
+	}
+
+	return
+}
+
+// CatalogEntry is a struct of API in catalog returned by CatalogJSON()
+//
+// Description:
+// Name - API name
+// Subdomain - API subdomain
+// DocumentationURL - URL of docs hosted on apiary.io
+// Visibility - "public" or "private"
+// Team - name of team owning API, "" for personal APIs
+type CatalogEntry struct {
+	Name             string `json:"name"`
+	Subdomain        string `json:"subdomain"`
+	DocumentationURL string `json:"documentationUrl"`
+	Visibility       string `json:"visibility"`
+	Team             string `json:"team"`
+}
+
+// CatalogJSON return JSON document with all user APIs, personal and of every
+// user team, normalized to CatalogEntry. As with GetAllApis() catalog is
+// returned along with TeamErrors when some teams fail.
+func (a *Apiary) CatalogJSON() (catalog []byte, err error) {
+	entries, err := a.catalog()
+	if _, partial := err.(TeamErrors); err != nil && !partial {
+		return
+	}
+
+	catalog, jsonErr := json.Marshal(map[string][]CatalogEntry{
+		"apis": entries,
+	})
+	if jsonErr != nil {
+		return nil, jsonErr
+	}
+
+	return
+}
+
+// catalog return catalog entries of all user APIs
+func (a *Apiary) catalog() (entries []CatalogEntry, err error) {
+	me, err := a.Me()
+	if err != nil {
+		return
+	}
+
+	return a.catalogOf(me)
+}
+
+// catalogOf return catalog entries of APIs of user and user teams
+func (a *Apiary) catalogOf(me ApiaryMeResponse) (entries []CatalogEntry, err error) {
+	apis, err := a.GetApis()
+	if err != nil {
+		return
+	}
+
+	owners := make(map[string]string)
+	errs := make(TeamErrors)
+	var teamApis []ApiaryApiResponse
+	for _, team := range me.Teams {
+		r, teamErr := a.GetTeamApis(team.ID)
+		if teamErr != nil {
+			errs[team.ID] = teamErr
+			continue
+		}
+
+		for _, api := range r.Apis {
+			if _, ok := owners[api.Subdomain]; !ok {
+				owners[api.Subdomain] = team.Name
+				teamApis = append(teamApis, api)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	entries = []CatalogEntry{}
+	for _, api := range append(apis.Apis, teamApis...) {
+		if seen[api.Subdomain] {
+			continue
+		}
+		seen[api.Subdomain] = true
+
+		entry := CatalogEntry{
+			Name:             api.Name,
+			Subdomain:        api.Subdomain,
+			DocumentationURL: api.DocumentationURL,
+			Visibility:       "public",
+			Team:             owners[api.Subdomain],
+		}
+
+		if entry.DocumentationURL == "" {
+			entry.DocumentationURL = DocumentationURL(api.Subdomain)
+		}
+
+		if api.Private {
+			entry.Visibility = "private"
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(errs) > 0 {
+		err = errs
+	}
+
+	return
+}
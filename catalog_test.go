@@ -0,0 +1,335 @@
+package apiary
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+// mockCatalog registers responders of user with personal API and two teams
+func mockCatalog() {
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionMe, httpmock.NewStringResponder(200, `{
+		"userId": "1",
+		"userName": "user",
+		"teams": [
+			{"teamId": "t1", "teamName": "First"},
+			{"teamId": "t2", "teamName": "Second"}
+		]
+	}`))
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionGetApis, httpmock.NewStringResponder(200, `{"apis": [
+		{"apiName": "Personal", "apiSubdomain": "personal", "apiIsPersonal": true, "apiIsPrivate": true},
+		{"apiName": "Shared", "apiSubdomain": "shared", "apiIsTeam": true, "apiIsPublic": true}
+	]}`))
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionGetTeamApis, "t1"), httpmock.NewStringResponder(200, `{"apis": [
+		{"apiName": "Shared", "apiSubdomain": "shared", "apiIsTeam": true, "apiIsPublic": true},
+		{"apiName": "First", "apiSubdomain": "first", "apiIsTeam": true, "apiIsPrivate": true}
+	]}`))
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionGetTeamApis, "t2"), httpmock.NewStringResponder(200, `{"apis": [
+		{"apiName": "Second", "apiSubdomain": "second", "apiIsTeam": true, "apiIsPublic": true}
+	]}`))
+}
+
+func subdomains(apis []ApiaryApiResponse) string {
+	names := make([]string, 0, len(apis))
+	for _, api := range apis {
+		names = append(names, api.Subdomain)
+	}
+
+	return strings.Join(names, ",")
+}
+
+func TestApiary_GetAllApis(t *testing.T) {
+	t.Run("Aggregate personal and team APIs", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.GetAllApis()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if subdomains(r.Apis) != "personal,shared,first,second" {
+			t.Errorf("Wrong apis: %s", subdomains(r.Apis))
+		}
+	})
+
+	t.Run("Return partial result when team fails", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionGetTeamApis, "t1"), httpmock.NewStringResponder(403, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.GetAllApis()
+
+		errs, ok := err.(TeamErrors)
+		if !ok {
+			t.Fatalf("Should return TeamErrors, got %v", err)
+		}
+
+		if len(errs) != 1 || errs["t1"] == nil {
+			t.Errorf("Wrong team errors: %v", errs)
+		}
+
+		if r == nil || subdomains(r.Apis) != "personal,shared,second" {
+			t.Error("APIs of other teams should be returned")
+		}
+	})
+
+	t.Run("Return error when user fails", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(401, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.GetAllApis()
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		if _, ok := err.(TeamErrors); ok {
+			t.Error("Should not return TeamErrors")
+		}
+	})
+}
+
+func TestApiary_CountApis(t *testing.T) {
+	t.Run("Count listed APIs", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		count, err := a.CountApis()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if count != 2 {
+			t.Errorf("Wrong count: %d", count)
+		}
+	})
+
+	t.Run("Return error on failed list", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(500, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.CountApis()
+		if err == nil {
+			t.Error("Should return Error")
+		}
+	})
+}
+
+func TestApiary_AllRepositoryNames(t *testing.T) {
+	t.Run("Deduplicate and sort names", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionGetTeamApis, "t2"), httpmock.NewStringResponder(200, `{"apis": [
+			{"apiName": "Second", "apiSubdomain": "second", "apiIsTeam": true},
+			{"apiName": "Alpha", "apiSubdomain": "alpha", "apiIsTeam": true},
+			{"apiName": "First", "apiSubdomain": "first", "apiIsTeam": true},
+			{"apiName": "Personal", "apiSubdomain": "personal", "apiIsTeam": true}
+		]}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		names, err := a.AllRepositoryNames()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if s := strings.Join(names, ","); s != "alpha,first,personal,second,shared" {
+			t.Errorf("Wrong names: %s", s)
+		}
+	})
+
+	t.Run("Return partial result when team fails", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionGetTeamApis, "t2"), httpmock.NewStringResponder(403, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		names, err := a.AllRepositoryNames()
+		if _, ok := err.(TeamErrors); !ok {
+			t.Fatalf("Should return TeamErrors, got %v", err)
+		}
+
+		if s := strings.Join(names, ","); s != "first,personal,shared" {
+			t.Errorf("Wrong names: %s", s)
+		}
+	})
+}
+
+func Test_TeamErrors(t *testing.T) {
+	err := TeamErrors{
+		"b": fmt.Errorf("second"),
+		"a": fmt.Errorf("first"),
+	}
+
+	if err.Error() != "Failed to fetch team APIs: a: first; b: second" {
+		t.Errorf("Wrong error message: %s", err.Error())
+	}
+}
+
+func TestApiary_GroupApisByTeam(t *testing.T) {
+	t.Run("Group APIs by team name", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionGetTeamApis, "t2"), httpmock.NewStringResponder(200, `{"apis": []}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		groups, err := a.GroupApisByTeam()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(groups) != 3 {
+			t.Errorf("Expected 3 groups, got: %v", groups)
+		}
+
+		if subdomains(groups[PersonalGroup]) != "personal" {
+			t.Errorf("Wrong personal apis: %s", subdomains(groups[PersonalGroup]))
+		}
+
+		if subdomains(groups["First"]) != "shared,first" {
+			t.Errorf("Wrong First team apis: %s", subdomains(groups["First"]))
+		}
+
+		if second, ok := groups["Second"]; !ok || second == nil || len(second) != 0 {
+			t.Errorf("Team with no APIs should have empty list, got: %v", second)
+		}
+	})
+
+	t.Run("Reject colliding team names", func(t *testing.T) {
+		for _, names := range [][2]string{{"First", "First"}, {"First", PersonalGroup}} {
+			httpmock.Activate()
+
+			mockCatalog()
+			httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionMe, httpmock.NewStringResponder(200, fmt.Sprintf(`{
+				"userId": "1",
+				"teams": [
+					{"teamId": "t1", "teamName": %q},
+					{"teamId": "t2", "teamName": %q}
+				]
+			}`, names[0], names[1])))
+
+			a := New(ApiaryOptions{
+				Token: Token,
+			})
+
+			_, err := a.GroupApisByTeam()
+			if err != ErrDuplicateGroup {
+				t.Errorf("Expected ErrDuplicateGroup for %v, got: %v", names, err)
+			}
+
+			httpmock.DeactivateAndReset()
+		}
+	})
+}
+
+func TestApiary_CatalogJSON(t *testing.T) {
+	t.Run("Build catalog", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.CatalogJSON()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		expected := `{"apis":[` +
+			`{"name":"Personal","subdomain":"personal","documentationUrl":"https://personal.docs.apiary.io/","visibility":"private","team":""},` +
+			`{"name":"Shared","subdomain":"shared","documentationUrl":"https://shared.docs.apiary.io/","visibility":"public","team":"First"},` +
+			`{"name":"First","subdomain":"first","documentationUrl":"https://first.docs.apiary.io/","visibility":"private","team":"First"},` +
+			`{"name":"Second","subdomain":"second","documentationUrl":"https://second.docs.apiary.io/","visibility":"public","team":"Second"}` +
+			`]}`
+
+		if string(r) != expected {
+			t.Errorf("Wrong catalog: %s", r)
+		}
+	})
+
+	t.Run("Return partial catalog", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionGetTeamApis, "t2"), httpmock.NewStringResponder(403, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.CatalogJSON()
+		if _, ok := err.(TeamErrors); !ok {
+			t.Errorf("Should return TeamErrors, got %v", err)
+		}
+
+		if !strings.Contains(string(r), `"subdomain":"first"`) || strings.Contains(string(r), `"subdomain":"second"`) {
+			t.Errorf("Wrong catalog: %s", r)
+		}
+	})
+
+	t.Run("Return error when user fails", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(401, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.CatalogJSON()
+		if err == nil || r != nil {
+			t.Error("Should return Error")
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package apiary
+
+import (
+	"time"
+)
+
+// Clock is a source of time used by client for delays and timestamps, so
+// tests can advance time without sleeping
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is a Clock backed by time package
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (a *Apiary) clock() Clock {
+	if a.options.Clock != nil {
+		return a.options.Clock
+	}
+
+	return realClock{}
+}
@@ -0,0 +1,90 @@
+package apiary
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+// fakeClock is a Clock which advances immediately on After
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	delays []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	c.delays = append(c.delays, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeClock) Delays() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]time.Duration(nil), c.delays...)
+}
+
+func Test_Clock(t *testing.T) {
+	t.Run("Use real clock by default", func(t *testing.T) {
+		a := New(ApiaryOptions{})
+
+		if _, ok := a.clock().(realClock); !ok {
+			t.Error("Real clock should be used")
+		}
+	})
+
+	t.Run("Wait retry delay on configured clock", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(503, `{}`), nil
+		})
+
+		clock := newFakeClock()
+		a := New(ApiaryOptions{
+			Token: Token,
+			Clock: clock,
+			RetryPolicy: RetryPolicy{
+				MaxAttempts: 3,
+				Delay:       time.Hour,
+			},
+		})
+
+		start := time.Now()
+		a.Me()
+
+		if time.Since(start) > time.Second {
+			t.Error("Retry should not sleep with fake clock")
+		}
+
+		delays := clock.Delays()
+		if len(delays) != 2 || delays[0] != time.Hour || delays[1] != time.Hour {
+			t.Errorf("Wrong retry delays: %v", delays)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package apiary
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout default request timeout of NewApiaryFromConfig clients
+	DefaultTimeout = 30 * time.Second
+	// DefaultUserAgent default User-Agent of NewApiaryFromConfig clients
+	DefaultUserAgent = "m1ome/apiary"
+	// DefaultMaxAttempts default number of request attempts of
+	// NewApiaryFromConfig clients
+	DefaultMaxAttempts = 3
+	// DefaultRetryDelay default delay between request attempts of
+	// NewApiaryFromConfig clients
+	DefaultRetryDelay = time.Second
+)
+
+// Config structure of client configuration, zero-valued options are replaced
+// by defaults:
+// Timeout - DefaultTimeout
+// UserAgent - DefaultUserAgent
+// RetryPolicy - DefaultMaxAttempts attempts with DefaultRetryDelay between
+// them, applied when RetryPolicy.MaxAttempts is zero
+type Config struct {
+	ApiaryOptions
+}
+
+// NewApiaryFromConfig create new Apiary.io client with defaults applied to
+// zero-valued options
+func NewApiaryFromConfig(cfg Config) *Apiary {
+	return New(cfg.options())
+}
+
+// options return client options with defaults applied
+func (c Config) options() ApiaryOptions {
+	opts := c.ApiaryOptions
+
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+
+	if opts.RetryPolicy.MaxAttempts == 0 {
+		opts.RetryPolicy.MaxAttempts = DefaultMaxAttempts
+		if opts.RetryPolicy.Delay == 0 {
+			opts.RetryPolicy.Delay = DefaultRetryDelay
+		}
+	}
+
+	return opts
+}
+
+// Config return effective client options, with defaults of options which
+// have them applied and tokens masked, for debugging
+func (a *Apiary) Config() ApiaryOptions {
+	opts := a.options
+
+	opts.Token = maskToken(opts.Token)
+	if opts.Tokens != nil {
+		opts.Tokens = make([]string, len(a.options.Tokens))
+		for i, token := range a.options.Tokens {
+			opts.Tokens[i] = maskToken(token)
+		}
+	}
+
+	opts.BaseURL = a.baseURL()
+	opts.Timeout = a.client.Timeout
+	opts.MaxPublishBytes = a.maxPublishBytes()
+	opts.RetryPolicy.MaxAttempts = opts.RetryPolicy.attempts()
+
+	if opts.Parallelism <= 0 {
+		opts.Parallelism = DefaultParallelism
+	}
+
+	if opts.Clock == nil {
+		opts.Clock = a.clock()
+	}
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = a.client
+	}
+
+	return opts
+}
+
+// AuthHeaders return auth headers client sends with configured token, for
+// replicating requests in other tools: Authorization of modern endpoints and
+// Authentication of legacy ones. Unlike Config() token is not masked, keep
+// headers out of logs.
+func (a *Apiary) AuthHeaders() (headers map[string]string, err error) {
+	tokens, err := a.tokens()
+	if err != nil {
+		return
+	}
+
+	headers = map[string]string{
+		"Authorization":  bearerToken(tokens[0]),
+		"Authentication": bearerTokenLegacy(tokens[0]),
+	}
+
+	return
+}
+
+// maskToken return token with all but last 4 characters masked, short tokens
+// are masked completely
+func maskToken(token string) string {
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+
+	return strings.Repeat("*", len(token)-4) + token[len(token)-4:]
+}
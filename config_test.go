@@ -0,0 +1,169 @@
+package apiary
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestNewApiaryFromConfig(t *testing.T) {
+	t.Run("Apply defaults to zero-valued options", func(t *testing.T) {
+		a := NewApiaryFromConfig(Config{
+			ApiaryOptions: ApiaryOptions{
+				Token: Token,
+			},
+		})
+
+		if a.options.Timeout != DefaultTimeout || a.client.Timeout != DefaultTimeout {
+			t.Errorf("Wrong timeout: %s", a.options.Timeout)
+		}
+
+		if a.options.UserAgent != DefaultUserAgent {
+			t.Errorf("Wrong user agent: %s", a.options.UserAgent)
+		}
+
+		if a.options.RetryPolicy.MaxAttempts != DefaultMaxAttempts || a.options.RetryPolicy.Delay != DefaultRetryDelay {
+			t.Errorf("Wrong retry policy: %+v", a.options.RetryPolicy)
+		}
+
+		if a.options.Token != Token {
+			t.Error("Token should be kept")
+		}
+	})
+
+	t.Run("Keep configured options", func(t *testing.T) {
+		a := NewApiaryFromConfig(Config{
+			ApiaryOptions: ApiaryOptions{
+				Timeout:   time.Minute,
+				UserAgent: "custom",
+				RetryPolicy: RetryPolicy{
+					MaxAttempts: 1,
+				},
+			},
+		})
+
+		if a.options.Timeout != time.Minute {
+			t.Errorf("Wrong timeout: %s", a.options.Timeout)
+		}
+
+		if a.options.UserAgent != "custom" {
+			t.Errorf("Wrong user agent: %s", a.options.UserAgent)
+		}
+
+		if a.options.RetryPolicy.MaxAttempts != 1 || a.options.RetryPolicy.Delay != 0 {
+			t.Errorf("Wrong retry policy: %+v", a.options.RetryPolicy)
+		}
+	})
+
+	t.Run("Send User-Agent", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var userAgent string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			userAgent = req.Header.Get("User-Agent")
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+		a := NewApiaryFromConfig(Config{})
+
+		a.Me()
+
+		if userAgent != DefaultUserAgent {
+			t.Errorf("Wrong User-Agent sent: %s", userAgent)
+		}
+	})
+}
+
+func TestApiary_Config(t *testing.T) {
+	t.Run("Return defaults with masked token", func(t *testing.T) {
+		a := NewApiaryFromConfig(Config{
+			ApiaryOptions: ApiaryOptions{
+				Token:  "0123456789abcdef",
+				Tokens: []string{"short", "fedcba9876543210"},
+			},
+		})
+
+		cfg := a.Config()
+		if cfg.Token != "************cdef" {
+			t.Errorf("Token should be masked, got: %s", cfg.Token)
+		}
+
+		if len(cfg.Tokens) != 2 || cfg.Tokens[0] != "*****" || cfg.Tokens[1] != "************3210" {
+			t.Errorf("Tokens should be masked, got: %v", cfg.Tokens)
+		}
+
+		if cfg.BaseURL != ApiaryAPIURL {
+			t.Errorf("Wrong base URL: %s", cfg.BaseURL)
+		}
+
+		if cfg.Timeout != DefaultTimeout || cfg.UserAgent != DefaultUserAgent {
+			t.Errorf("Wrong timeout %s or user agent %s", cfg.Timeout, cfg.UserAgent)
+		}
+
+		if cfg.Parallelism != DefaultParallelism || cfg.MaxPublishBytes != DefaultMaxPublishBytes {
+			t.Errorf("Wrong parallelism %d or max publish bytes %d", cfg.Parallelism, cfg.MaxPublishBytes)
+		}
+
+		if cfg.RetryPolicy.MaxAttempts != DefaultMaxAttempts {
+			t.Errorf("Wrong retry policy: %+v", cfg.RetryPolicy)
+		}
+
+		if cfg.Clock == nil || cfg.HTTPClient == nil {
+			t.Error("Clock and HTTP client in effect should be returned")
+		}
+
+		if a.options.Token != "0123456789abcdef" || a.options.Tokens[1] != "fedcba9876543210" {
+			t.Error("Client tokens should not be changed")
+		}
+	})
+}
+
+func TestApiary_AuthHeaders(t *testing.T) {
+	t.Run("Return headers of both schemes", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var sent http.Header
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			sent = req.Header
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: "secret-token",
+		})
+
+		headers, err := a.AuthHeaders()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if headers["Authorization"] != "bearer secret-token" {
+			t.Errorf("Wrong modern header: %s", headers["Authorization"])
+		}
+
+		if headers["Authentication"] != "Token secret-token" {
+			t.Errorf("Wrong legacy header: %s", headers["Authentication"])
+		}
+
+		a.Me()
+		if sent.Get("Authorization") != headers["Authorization"] {
+			t.Error("Returned header should match sent one")
+		}
+	})
+
+	t.Run("Return error of credential store", func(t *testing.T) {
+		a := New(ApiaryOptions{
+			CredentialStore: &memoryStore{err: errors.New("Keychain locked")},
+		})
+
+		_, err := a.AuthHeaders()
+		if err == nil {
+			t.Error("Should return Error")
+		}
+	})
+}
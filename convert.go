@@ -0,0 +1,34 @@
+package apiary
+
+// Converter is an interface of API Blueprint to OpenAPI converter used by
+// ConvertToOpenAPI(), so package doesn't depend on any converter library
+type Converter interface {
+	Convert(blueprint []byte) (openapi []byte, err error)
+}
+
+// ConvertToOpenAPI converts blueprint to OpenAPI document locally with
+// configured Converter. ErrNoConverter is returned when there is none.
+func (a *Apiary) ConvertToOpenAPI(blueprint []byte) (openapi []byte, err error) {
+	if a.options.Converter == nil {
+		err = ErrNoConverter
+		return
+	}
+
+	return a.options.Converter.Convert(blueprint)
+}
+
+// FetchOpenAPI fetches blueprint of API and converts it to OpenAPI document
+// with ConvertToOpenAPI()
+func (a *Apiary) FetchOpenAPI(name string) (openapi []byte, err error) {
+	if a.options.Converter == nil {
+		err = ErrNoConverter
+		return
+	}
+
+	blueprint, err := a.FetchBlueprint(name)
+	if err != nil {
+		return
+	}
+
+	return a.ConvertToOpenAPI([]byte(blueprint.Code))
+}
@@ -0,0 +1,75 @@
+package apiary
+
+import (
+	"fmt"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+type stubConverter struct {
+	converted []byte
+}
+
+func (c *stubConverter) Convert(blueprint []byte) ([]byte, error) {
+	c.converted = blueprint
+	return []byte(`{"openapi": "3.0.0"}`), nil
+}
+
+func TestApiary_ConvertToOpenAPI(t *testing.T) {
+	t.Run("Convert with configured converter", func(t *testing.T) {
+		converter := &stubConverter{}
+		a := New(ApiaryOptions{
+			Token:     Token,
+			Converter: converter,
+		})
+
+		openapi, err := a.ConvertToOpenAPI(ValidBlueprint)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if string(openapi) != `{"openapi": "3.0.0"}` {
+			t.Errorf("Wrong OpenAPI document: %s", openapi)
+		}
+
+		if string(converter.converted) != string(ValidBlueprint) {
+			t.Error("Blueprint should be passed to converter")
+		}
+	})
+
+	t.Run("Fail without converter", func(t *testing.T) {
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.ConvertToOpenAPI(ValidBlueprint)
+		if err != ErrNoConverter {
+			t.Errorf("Expected ErrNoConverter, got: %v", err)
+		}
+	})
+}
+
+func TestApiary_FetchOpenAPI(t *testing.T) {
+	t.Run("Convert fetched blueprint", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, Repository), httpmock.NewStringResponder(200, `{"code": "FORMAT: 1A\n\n# API"}`))
+
+		converter := &stubConverter{}
+		a := New(ApiaryOptions{
+			Token:     Token,
+			Converter: converter,
+		})
+
+		_, err := a.FetchOpenAPI(Repository)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if string(converter.converted) != "FORMAT: 1A\n\n# API" {
+			t.Errorf("Fetched blueprint should be converted, got: %q", converter.converted)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package apiary
+
+// CredentialStore is an interface of token storage, like OS keychain, client
+// reads token from when Token option is empty
+type CredentialStore interface {
+	Get() (token string, err error)
+	Set(token string) error
+}
+
+// storedToken return token from CredentialStore, reading it once on first
+// use
+func (a *Apiary) storedToken() (token string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" {
+		return a.token, nil
+	}
+
+	a.token, err = a.options.CredentialStore.Get()
+	return a.token, err
+}
@@ -0,0 +1,115 @@
+package apiary
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+// memoryStore is a CredentialStore keeping token in memory
+type memoryStore struct {
+	mu    sync.Mutex
+	token string
+	err   error
+	reads int
+}
+
+func (s *memoryStore) Get() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reads++
+	return s.token, s.err
+}
+
+func (s *memoryStore) Set(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = token
+	return nil
+}
+
+func TestApiary_CredentialStore(t *testing.T) {
+	t.Run("Read token from store lazily", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var auth []string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			auth = append(auth, req.Header.Get("Authorization"))
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		store := &memoryStore{}
+		a := New(ApiaryOptions{
+			CredentialStore: store,
+		})
+		store.Set("stored")
+
+		if store.reads != 0 {
+			t.Error("Token should not be read before first request")
+		}
+
+		a.Me()
+		a.Me()
+
+		if len(auth) != 2 || auth[0] != "bearer stored" || auth[1] != "bearer stored" {
+			t.Errorf("Stored token should be sent, got: %v", auth)
+		}
+
+		if store.reads != 1 {
+			t.Errorf("Token should be read once, got %d reads", store.reads)
+		}
+	})
+
+	t.Run("Prefer Token option", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var auth string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			auth = req.Header.Get("Authorization")
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		store := &memoryStore{token: "stored"}
+		a := New(ApiaryOptions{
+			Token:           "option",
+			CredentialStore: store,
+		})
+
+		a.Me()
+		if auth != "bearer option" || store.reads != 0 {
+			t.Errorf("Token option should be used, got: %s", auth)
+		}
+	})
+
+	t.Run("Fail when store fails", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		storeErr := errors.New("keychain is locked")
+		a := New(ApiaryOptions{
+			CredentialStore: &memoryStore{err: storeErr},
+		})
+
+		_, err := a.Me()
+		if err != storeErr {
+			t.Errorf("Store error should be returned, got: %v", err)
+		}
+
+		if requests != 0 {
+			t.Error("No request should be made without token")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package apiary
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// lookupHost resolves host names, replaced in tests
+var lookupHost = net.LookupHost
+
+// Diagnostics is a struct of answer to Diagnose() call
+//
+// Description:
+// BaseURL - URL of apiary.io API in effect
+// Resolves - does BaseURL host resolve
+// Proxy - URL of proxy requests are sent through, "" when none
+// ProxyResolves - does proxy host resolve
+// Reachable - did API answer request
+// TokenValid - did API accept token
+// Latency - duration of request to API
+// Problems - human readable descriptions of found problems
+type Diagnostics struct {
+	BaseURL       string
+	Resolves      bool
+	Proxy         string
+	ProxyResolves bool
+	Reachable     bool
+	TokenValid    bool
+	Latency       time.Duration
+	Problems      []string
+}
+
+// Diagnose checks connectivity to apiary.io and returns report of every
+// check instead of failing on first problem. Error is returned only when
+// checks can't be made.
+func (a *Apiary) Diagnose() (report *Diagnostics, err error) {
+	base, err := url.Parse(a.baseURL())
+	if err != nil {
+		return
+	}
+
+	report = &Diagnostics{
+		BaseURL: base.String(),
+	}
+
+	report.Resolves = a.resolves(base.Hostname(), "API host", report)
+
+	proxy, err := a.proxyURL(base)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxy != nil {
+		report.Proxy = proxy.String()
+		report.ProxyResolves = a.resolves(proxy.Hostname(), "Proxy host", report)
+	}
+
+	start := a.clock().Now()
+	_, response, reqErr := a.send(context.Background(), EndpointModern, "GET", apiaryActionMe, nil, nil)
+	report.Latency = a.clock().Now().Sub(start)
+
+	if response == nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("API is unreachable: %s", reqErr))
+		return
+	}
+
+	report.Reachable = true
+	switch {
+	case response.StatusCode == http.StatusOK:
+		report.TokenValid = true
+	case unauthorized(response):
+		report.Problems = append(report.Problems, fmt.Sprintf("Token is rejected: %s", response.Status))
+	default:
+		report.Problems = append(report.Problems, fmt.Sprintf("API answered with: %s", response.Status))
+	}
+
+	return
+}
+
+// resolves reports whether host resolves, adding problem to report when not
+func (a *Apiary) resolves(host string, name string, report *Diagnostics) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+
+	_, err := lookupHost(host)
+	if err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("%s %s doesn't resolve: %s", name, host, err))
+		return false
+	}
+
+	return true
+}
+
+// proxyURL return proxy first request attempt to base is sent through
+func (a *Apiary) proxyURL(base *url.URL) (*url.URL, error) {
+	if selector := a.options.RetryPolicy.ProxySelector; selector != nil {
+		if proxy := selector(1); proxy != nil {
+			return proxy, nil
+		}
+	}
+
+	return http.ProxyFromEnvironment(&http.Request{URL: base})
+}
@@ -0,0 +1,119 @@
+package apiary
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_Diagnose(t *testing.T) {
+	defer func(lookup func(string) ([]string, error)) {
+		lookupHost = lookup
+	}(lookupHost)
+
+	lookupHost = func(host string) ([]string, error) {
+		if host == "api.apiary.io" {
+			return []string{"10.0.0.1"}, nil
+		}
+
+		return nil, errors.New("no such host")
+	}
+
+	t.Run("Report healthy connection", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		clock := newFakeClock()
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			<-clock.After(30 * time.Millisecond)
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			Clock: clock,
+		})
+
+		report, err := a.Diagnose()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if report.BaseURL != ApiaryAPIURL || !report.Resolves || !report.Reachable || !report.TokenValid {
+			t.Errorf("Wrong report: %+v", report)
+		}
+
+		if report.Latency != 30*time.Millisecond {
+			t.Errorf("Latency should be measured with Clock, got %s", report.Latency)
+		}
+
+		if len(report.Problems) != 0 {
+			t.Errorf("No problems expected: %v", report.Problems)
+		}
+	})
+
+	t.Run("Report rejected token and unresolved proxy", func(t *testing.T) {
+		a := New(ApiaryOptions{
+			Token: Token,
+			// proxy transport is not mocked, answer before it
+			Middlewares: []Middleware{
+				func(next http.RoundTripper) http.RoundTripper {
+					return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+						return httpmock.NewStringResponse(401, `{}`), nil
+					})
+				},
+			},
+			RetryPolicy: RetryPolicy{
+				ProxySelector: func(attempt int) *url.URL {
+					return &url.URL{Scheme: "http", Host: "proxy.local:3128"}
+				},
+			},
+		})
+
+		report, err := a.Diagnose()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !report.Reachable || report.TokenValid {
+			t.Errorf("API should be reachable with invalid token: %+v", report)
+		}
+
+		if report.Proxy != "http://proxy.local:3128" || report.ProxyResolves {
+			t.Errorf("Wrong proxy report: %+v", report)
+		}
+
+		if len(report.Problems) != 2 {
+			t.Errorf("Expected 2 problems, got: %v", report.Problems)
+		}
+	})
+
+	t.Run("Report unreachable API", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewErrorResponder(errors.New("connection refused")))
+
+		a := New(ApiaryOptions{
+			Token:   Token,
+			BaseURL: "https://apiary.internal/",
+		})
+
+		report, err := a.Diagnose()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if report.Resolves || report.Reachable || report.TokenValid {
+			t.Errorf("Wrong report: %+v", report)
+		}
+
+		if len(report.Problems) != 2 {
+			t.Errorf("Expected 2 problems, got: %v", report.Problems)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package apiary
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DiscoveredApi is a struct of API sent by DiscoverApis()
+//
+// Description:
+// Api - API as listed by apiary.io
+// Team - team owning API, nil for personal APIs
+type DiscoveredApi struct {
+	Api  ApiaryApiResponse
+	Team *ApiaryTeam
+}
+
+// DiscoverApis sends APIs user can access, personal and of every user team,
+// as their lists are fetched. Lists are fetched concurrently, no more than
+// Parallelism at once, and every API is sent once. Errors (TeamErrors for
+// teams which APIs couldn't be fetched) are sent after API channel is closed.
+// When ctx is done discovery stops and ctx error is sent. Both channels must
+// be drained, APIs first, they are closed when discovery finishes.
+func (a *Apiary) DiscoverApis(ctx context.Context) (<-chan DiscoveredApi, <-chan error) {
+	apis := make(chan DiscoveredApi)
+	errs := make(chan error)
+
+	go func() {
+		defer close(errs)
+
+		failures := a.discover(ctx, apis)
+		close(apis)
+
+		for _, err := range failures {
+			errs <- err
+		}
+	}()
+
+	return apis, errs
+}
+
+// discover sends APIs to apis and return errors of discovery
+func (a *Apiary) discover(ctx context.Context, apis chan<- DiscoveredApi) (failures []error) {
+	me, err := a.me(ctx)
+	if err != nil {
+		return []error{err}
+	}
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	fail := func(err error) {
+		mu.Lock()
+		failures = append(failures, err)
+		mu.Unlock()
+	}
+
+	a.parallel(len(me.Teams)+1, func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var team *ApiaryTeam
+		path := apiaryActionGetApis
+		if i > 0 {
+			team = &me.Teams[i-1]
+			path = fmt.Sprintf(apiaryActionGetTeamApis, team.ID)
+		}
+
+		list, _, err := a.listApis(ctx, path)
+		if err != nil {
+			if team != nil {
+				err = TeamErrors{team.ID: err}
+			}
+
+			fail(err)
+			return
+		}
+
+		for _, api := range list.Apis {
+			if team == nil && !api.Personal {
+				continue
+			}
+
+			mu.Lock()
+			duplicate := seen[api.Subdomain]
+			seen[api.Subdomain] = true
+			mu.Unlock()
+
+			if duplicate {
+				continue
+			}
+
+			select {
+			case apis <- DiscoveredApi{Api: api, Team: team}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	if ctx.Err() != nil {
+		failures = append(failures, ctx.Err())
+	}
+
+	return
+}
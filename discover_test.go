@@ -0,0 +1,134 @@
+package apiary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_DiscoverApis(t *testing.T) {
+	t.Run("Send APIs with owning team", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		apis, errs := a.DiscoverApis(context.Background())
+
+		var discovered []string
+		for api := range apis {
+			owner := "-"
+			if api.Team != nil {
+				owner = api.Team.Name
+			}
+
+			discovered = append(discovered, api.Api.Subdomain+":"+owner)
+		}
+		sort.Strings(discovered)
+
+		for err := range errs {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if s := strings.Join(discovered, ","); s != "first:First,personal:-,second:Second,shared:First" {
+			t.Errorf("Wrong APIs: %s", s)
+		}
+	})
+
+	t.Run("Send team errors after APIs", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionGetTeamApis, "t2"), httpmock.NewStringResponder(403, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		apis, errs := a.DiscoverApis(context.Background())
+
+		count := 0
+		for range apis {
+			count++
+		}
+
+		var failures []error
+		for err := range errs {
+			failures = append(failures, err)
+		}
+
+		if count != 3 {
+			t.Errorf("APIs of other scopes should be sent, got %d", count)
+		}
+
+		if len(failures) != 1 {
+			t.Fatalf("Expected one error, got %v", failures)
+		}
+
+		if teamErrs, ok := failures[0].(TeamErrors); !ok || teamErrs["t2"] == nil {
+			t.Errorf("Wrong error: %v", failures[0])
+		}
+	})
+
+	t.Run("Stop on canceled context", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		a := New(ApiaryOptions{
+			Token:       Token,
+			Parallelism: 1,
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		apis, errs := a.DiscoverApis(ctx)
+
+		<-apis
+		cancel()
+
+		for range apis {
+		}
+
+		var last error
+		for err := range errs {
+			last = err
+		}
+
+		if last != context.Canceled {
+			t.Errorf("Should send context.Canceled, got %v", last)
+		}
+	})
+
+	t.Run("Fail when user can't be fetched", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(500, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		apis, errs := a.DiscoverApis(context.Background())
+		for range apis {
+			t.Error("No API should be sent")
+		}
+
+		if err := <-errs; err == nil {
+			t.Error("Should send Error")
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package apiary
+
+import (
+	"context"
+	"net/http"
+)
+
+// FetchDocsHTML fetches rendered HTML documentation page of API hosted on
+// apiary.io. Request is authorized with token, so private docs can be
+// fetched by their members; ErrDocsForbidden is returned when access is
+// denied and ErrApiNotFound when there is no such API.
+func (a *Apiary) FetchDocsHTML(subdomain string) (html []byte, err error) {
+	html, response, err := a.send(context.Background(), EndpointModern, "GET", DocumentationURL(subdomain), nil, nil)
+	if response == nil {
+		return
+	}
+
+	switch response.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, ErrDocsForbidden
+	case http.StatusNotFound:
+		return nil, ErrApiNotFound
+	}
+
+	if err != nil {
+		return
+	}
+
+	err = checkOk(response)
+	if err != nil {
+		html = nil
+	}
+
+	return
+}
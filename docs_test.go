@@ -0,0 +1,56 @@
+package apiary
+
+import (
+	"net/http"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_FetchDocsHTML(t *testing.T) {
+	fetch := func(status int, body string) (html []byte, auth string, err error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", DocumentationURL(Repository), func(req *http.Request) (*http.Response, error) {
+			auth = req.Header.Get("Authorization")
+			return httpmock.NewStringResponse(status, body), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: "token",
+		})
+
+		html, err = a.FetchDocsHTML(Repository)
+		return
+	}
+
+	t.Run("Fetch docs page", func(t *testing.T) {
+		html, auth, err := fetch(200, `<html><body>API</body></html>`)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if string(html) != `<html><body>API</body></html>` {
+			t.Errorf("Wrong docs HTML: %s", html)
+		}
+
+		if auth != "bearer token" {
+			t.Errorf("Request should be authorized, got: %s", auth)
+		}
+	})
+
+	t.Run("Forbidden private docs", func(t *testing.T) {
+		_, _, err := fetch(403, `<html>Forbidden</html>`)
+		if err != ErrDocsForbidden {
+			t.Errorf("Expected ErrDocsForbidden, got: %v", err)
+		}
+	})
+
+	t.Run("Unknown API", func(t *testing.T) {
+		_, _, err := fetch(404, `<html>Not found</html>`)
+		if err != ErrApiNotFound {
+			t.Errorf("Expected ErrApiNotFound, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package apiary
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// LocalApi is a struct of API declared locally, compared with hosted APIs
+// by CatalogDrift()
+//
+// Description:
+// Subdomain - API subdomain
+// Blueprint - declared blueprint content
+type LocalApi struct {
+	Subdomain string
+	Blueprint []byte
+}
+
+// DriftReport is a struct of answer to CatalogDrift() call, every list is
+// sorted by subdomain
+//
+// Description:
+// Added - APIs declared locally, but not hosted
+// Removed - APIs hosted, but not declared locally
+// Changed - APIs which hosted blueprint differs from declared one
+// Unchanged - APIs which hosted blueprint matches declared one
+type DriftReport struct {
+	Added     []string
+	Removed   []string
+	Changed   []string
+	Unchanged []string
+}
+
+// CatalogDrift compares declared APIs with APIs user can access on
+// apiary.io. Blueprints are compared with line endings normalized, like
+// BlueprintChanged() does, no more than Parallelism at once. Any failure
+// fails whole comparison, as partial report would be misleading.
+func (a *Apiary) CatalogDrift(local []LocalApi) (report *DriftReport, err error) {
+	remote, err := a.GetAllApis()
+	if err != nil {
+		return
+	}
+
+	hosted := make(map[string]bool)
+	for _, api := range remote.Apis {
+		hosted[api.Subdomain] = true
+	}
+
+	report = &DriftReport{
+		Added:     []string{},
+		Removed:   []string{},
+		Changed:   []string{},
+		Unchanged: []string{},
+	}
+
+	declared := make(map[string]bool)
+	var common []LocalApi
+	for _, api := range local {
+		declared[api.Subdomain] = true
+		if hosted[api.Subdomain] {
+			common = append(common, api)
+		} else {
+			report.Added = append(report.Added, api.Subdomain)
+		}
+	}
+
+	for _, api := range remote.Apis {
+		if !declared[api.Subdomain] {
+			report.Removed = append(report.Removed, api.Subdomain)
+		}
+	}
+
+	errs := make(ApiErrors)
+	var mu sync.Mutex
+
+	a.parallel(len(common), func(i int) {
+		api := common[i]
+		changed, apiErr := a.BlueprintChanged(api.Subdomain, api.Blueprint)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case apiErr != nil:
+			errs[api.Subdomain] = apiErr
+		case changed:
+			report.Changed = append(report.Changed, api.Subdomain)
+		default:
+			report.Unchanged = append(report.Unchanged, api.Subdomain)
+		}
+	})
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	for _, list := range [][]string{report.Added, report.Removed, report.Changed, report.Unchanged} {
+		sort.Strings(list)
+	}
+
+	return
+}
+
+// ApplyOpts structure of ApplyCatalog() options
+// DryRun - Only report what would be done, without making changes.
+// Delete - Delete hosted APIs which are not declared locally.
+type ApplyOpts struct {
+	DryRun bool
+	Delete bool
+}
+
+// ApplyResult is a struct of answer to ApplyCatalog() call, every list is
+// sorted by subdomain
+//
+// Description:
+// DryRun - is nothing changed
+// Created - APIs published for the first time (or to be, with DryRun)
+// Updated - APIs which changed blueprint is published (or to be, with DryRun)
+// Deleted - APIs deleted, always empty as apiary.io API can't delete APIs
+// Unchanged - APIs left as is
+type ApplyResult struct {
+	DryRun    bool
+	Created   []string
+	Updated   []string
+	Deleted   []string
+	Unchanged []string
+}
+
+// ApplyCatalog makes APIs hosted on apiary.io match declared ones: blueprints
+// of added and changed APIs are published, no more than Parallelism at once,
+// and with Delete option removed APIs are deleted. Result has APIs which were
+// changed, APIs which failed are reported with ApiErrors. As apiary.io API
+// can't delete APIs, deletion fails with ErrDeleteUnsupported, with DryRun
+// as well, so plan doesn't promise changes apply can't make.
+func (a *Apiary) ApplyCatalog(local []LocalApi, opts ApplyOpts) (result *ApplyResult, err error) {
+	plan, err := a.CatalogDrift(local)
+	if err != nil {
+		return
+	}
+
+	result = &ApplyResult{
+		DryRun:    opts.DryRun,
+		Created:   []string{},
+		Updated:   []string{},
+		Deleted:   []string{},
+		Unchanged: plan.Unchanged,
+	}
+
+	errs := make(ApiErrors)
+	if opts.Delete {
+		for _, subdomain := range plan.Removed {
+			errs[subdomain] = ErrDeleteUnsupported
+		}
+	}
+
+	if opts.DryRun {
+		result.Created = plan.Added
+		result.Updated = plan.Changed
+		if len(errs) > 0 {
+			err = errs
+		}
+
+		return
+	}
+
+	blueprints := make(map[string][]byte)
+	for _, api := range local {
+		blueprints[api.Subdomain] = api.Blueprint
+	}
+
+	publish := make(map[string][]byte)
+	for _, subdomain := range append(append([]string{}, plan.Added...), plan.Changed...) {
+		publish[subdomain] = blueprints[subdomain]
+	}
+
+	published := a.PublishBlueprints(context.Background(), publish)
+	for _, subdomain := range plan.Added {
+		if publishErr, ok := published.Failed[subdomain]; ok {
+			errs[subdomain] = publishErr
+		} else {
+			result.Created = append(result.Created, subdomain)
+		}
+	}
+
+	for _, subdomain := range plan.Changed {
+		if publishErr, ok := published.Failed[subdomain]; ok {
+			errs[subdomain] = publishErr
+		} else {
+			result.Updated = append(result.Updated, subdomain)
+		}
+	}
+
+	if len(errs) > 0 {
+		err = errs
+	}
+
+	return
+}
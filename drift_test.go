@@ -0,0 +1,176 @@
+package apiary
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_CatalogDrift(t *testing.T) {
+	t.Run("Report added, removed and changed APIs", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		for _, subdomain := range []string{"personal", "shared", "first", "second"} {
+			httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, subdomain), httpmock.NewStringResponder(200, fmt.Sprintf(`{"code": "# %s\n"}`, subdomain)))
+		}
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		report, err := a.CatalogDrift([]LocalApi{
+			{Subdomain: "personal", Blueprint: []byte("# personal\r\n")},
+			{Subdomain: "shared", Blueprint: []byte("# shared, changed\n")},
+			{Subdomain: "first", Blueprint: []byte("# first\n")},
+			{Subdomain: "new", Blueprint: []byte("# new\n")},
+		})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if s := strings.Join(report.Added, ","); s != "new" {
+			t.Errorf("Wrong added: %s", s)
+		}
+
+		if s := strings.Join(report.Removed, ","); s != "second" {
+			t.Errorf("Wrong removed: %s", s)
+		}
+
+		if s := strings.Join(report.Changed, ","); s != "shared" {
+			t.Errorf("Wrong changed: %s", s)
+		}
+
+		if s := strings.Join(report.Unchanged, ","); s != "first,personal" {
+			t.Errorf("Wrong unchanged: %s", s)
+		}
+	})
+
+	t.Run("Fail when blueprint can't be compared", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, "first"), httpmock.NewStringResponder(500, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.CatalogDrift([]LocalApi{
+			{Subdomain: "first", Blueprint: []byte("# first\n")},
+		})
+		if _, ok := err.(ApiErrors); !ok {
+			t.Errorf("Expected ApiErrors, got: %v", err)
+		}
+	})
+}
+
+func TestApiary_ApplyCatalog(t *testing.T) {
+	local := []LocalApi{
+		{Subdomain: "personal", Blueprint: []byte("# personal\n")},
+		{Subdomain: "shared", Blueprint: []byte("# shared, changed\n")},
+		{Subdomain: "first", Blueprint: []byte("# first\n")},
+		{Subdomain: "new", Blueprint: []byte("# new\n")},
+	}
+
+	apply := func(opts ApplyOpts) (result *ApplyResult, published []string, err error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		for _, subdomain := range []string{"personal", "shared", "first", "second"} {
+			httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, subdomain), httpmock.NewStringResponder(200, fmt.Sprintf(`{"code": "# %s\n"}`, subdomain)))
+		}
+
+		var mu sync.Mutex
+		for _, subdomain := range []string{"shared", "new"} {
+			subdomain := subdomain
+			httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, subdomain), func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				published = append(published, subdomain)
+				mu.Unlock()
+
+				return httpmock.NewStringResponse(201, `{}`), nil
+			})
+		}
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		result, err = a.ApplyCatalog(local, opts)
+		sort.Strings(published)
+		return
+	}
+
+	t.Run("Create and update APIs", func(t *testing.T) {
+		result, published, err := apply(ApplyOpts{})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if s := strings.Join(published, ","); s != "new,shared" {
+			t.Errorf("Wrong published APIs: %s", s)
+		}
+
+		if strings.Join(result.Created, ",") != "new" || strings.Join(result.Updated, ",") != "shared" {
+			t.Errorf("Wrong result: %+v", result)
+		}
+
+		if len(result.Deleted) != 0 || strings.Join(result.Unchanged, ",") != "first,personal" {
+			t.Errorf("Wrong result: %+v", result)
+		}
+	})
+
+	t.Run("Report unsupported delete", func(t *testing.T) {
+		result, _, err := apply(ApplyOpts{Delete: true})
+
+		errs, ok := err.(ApiErrors)
+		if !ok || len(errs) != 1 || errs["second"] != ErrDeleteUnsupported {
+			t.Errorf("Delete should fail with ErrDeleteUnsupported, got: %v", err)
+		}
+
+		if len(result.Deleted) != 0 || len(result.Created) != 1 {
+			t.Errorf("Wrong result: %+v", result)
+		}
+	})
+
+	t.Run("Plan changes on dry run", func(t *testing.T) {
+		result, published, err := apply(ApplyOpts{DryRun: true})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(published) != 0 {
+			t.Errorf("Nothing should be published on dry run: %v", published)
+		}
+
+		if !result.DryRun || strings.Join(result.Created, ",") != "new" ||
+			strings.Join(result.Updated, ",") != "shared" || len(result.Deleted) != 0 {
+			t.Errorf("Wrong result: %+v", result)
+		}
+	})
+
+	t.Run("Report unsupported delete on dry run", func(t *testing.T) {
+		result, published, err := apply(ApplyOpts{DryRun: true, Delete: true})
+		errs, ok := err.(ApiErrors)
+		if !ok || len(errs) != 1 || errs["second"] != ErrDeleteUnsupported {
+			t.Errorf("Delete should fail with ErrDeleteUnsupported, got: %v", err)
+		}
+
+		if len(published) != 0 {
+			t.Errorf("Nothing should be published on dry run: %v", published)
+		}
+
+		if len(result.Deleted) != 0 || strings.Join(result.Created, ",") != "new" {
+			t.Errorf("Wrong result: %+v", result)
+		}
+	})
+}
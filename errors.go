@@ -0,0 +1,86 @@
+package apiary
+
+import (
+	"errors"
+)
+
+// ErrApiNotFound returned when API with a given subdomain is not accessible
+var ErrApiNotFound = errors.New("API not found")
+
+// ErrBlueprintTooLarge returned when blueprint content exceeds MaxPublishBytes
+var ErrBlueprintTooLarge = errors.New("Blueprint is too large")
+
+// ErrNoApiName returned when blueprint content has no API name heading
+var ErrNoApiName = errors.New("Blueprint has no API name")
+
+// ErrNoHost returned by BlueprintHost() when blueprint has no HOST metadata
+var ErrNoHost = errors.New("Blueprint has no HOST")
+
+// ErrResourceNotFound returned when blueprint has no requested resource
+var ErrResourceNotFound = errors.New("Resource not found")
+
+// ErrMockUnavailable returned when mock server URL of API can't be provided
+var ErrMockUnavailable = errors.New("Mock server is unavailable")
+
+// ErrInvalidBlueprint returned when blueprint has parser errors
+var ErrInvalidBlueprint = errors.New("Blueprint is invalid")
+
+// ErrTooManyWarnings returned when blueprint has more parser warnings than
+// allowed
+var ErrTooManyWarnings = errors.New("Blueprint has too many warnings")
+
+// ErrTeamNotFound returned when user has no team with a given name or ID
+var ErrTeamNotFound = errors.New("Team not found")
+
+// ErrNoTeams returned when user has no teams
+var ErrNoTeams = errors.New("User has no teams")
+
+// ErrClientClosed returned for requests made after Shutdown
+var ErrClientClosed = errors.New("Client is closed")
+
+// ErrInvalidSubdomain returned when subdomain is malformed or reserved
+var ErrInvalidSubdomain = errors.New("Invalid subdomain")
+
+// ErrNoConverter returned when OpenAPI conversion is requested without
+// Converter option
+var ErrNoConverter = errors.New("No OpenAPI converter configured")
+
+// ErrNoParser returned when blueprint validation is requested without
+// ParserURL option
+var ErrNoParser = errors.New("No API Blueprint parser configured")
+
+// ErrChecksumMismatch returned when response body doesn't match its
+// Content-MD5 header
+var ErrChecksumMismatch = errors.New("Response checksum mismatch")
+
+// ErrReadOnly returned by mutating methods of client in ReadOnly mode
+var ErrReadOnly = errors.New("Client is read-only")
+
+// ErrDocsForbidden returned when access to private API docs is denied
+var ErrDocsForbidden = errors.New("Access to API docs is forbidden")
+
+// ErrDeleteUnsupported returned when API deletion is requested, apiary.io
+// API has no endpoint deleting APIs
+var ErrDeleteUnsupported = errors.New("Deleting APIs is not supported")
+
+// ErrForeignURL returned when URL given to fetch from is not on apiary.io
+// host
+var ErrForeignURL = errors.New("URL is not on apiary.io host")
+
+// ErrSwapVerification returned by SwapBlueprint() when blueprint fetched
+// after publish doesn't match published one
+var ErrSwapVerification = errors.New("Published blueprint doesn't match")
+
+// ErrFormatMismatch returned when published content is not in format given
+// with PublishOptions
+var ErrFormatMismatch = errors.New("Content doesn't match document format")
+
+// ErrInvalidRange returned when range start is negative
+var ErrInvalidRange = errors.New("Invalid range start")
+
+// ErrDuplicateGroup returned by GroupApisByTeam() when team name is used by
+// other team or is PersonalGroup
+var ErrDuplicateGroup = errors.New("Team name is not unique")
+
+// ErrInvalidInterval returned when polling interval is not positive
+var ErrInvalidInterval = errors.New("Interval must be positive")
@@ -0,0 +1,190 @@
+package apiary
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// Example is a struct of request/response example of blueprint resource
+//
+// Description:
+// Action - name of action example belongs to
+// Method - HTTP method of action
+// Type - "request" or "response"
+// Name - request name or response status code
+// ContentType - example media type
+// Body - example body
+type Example struct {
+	Action      string
+	Method      string
+	Type        string
+	Name        string
+	ContentType string
+	Body        string
+}
+
+// GetExamples return request/response examples of a blueprint resource.
+// Resource is matched by its name or URI template, examples are parsed from
+// fetched blueprint content as apiary.io has no examples endpoint.
+// ErrResourceNotFound returned when blueprint has no such resource.
+func (a *Apiary) GetExamples(subdomain string, resource string) (examples []Example, err error) {
+	blueprint, err := a.FetchBlueprint(subdomain)
+	if err != nil {
+		return
+	}
+
+	return parseExamples([]byte(blueprint.Code), resource)
+}
+
+// parseExamples extracts examples of resource from blueprint content
+func parseExamples(content []byte, resource string) ([]Example, error) {
+	var examples []Example
+	var current *Example
+	var found, inResource, inBody bool
+	var action, method string
+	var body []string
+
+	flush := func() {
+		if current != nil {
+			current.Body = strings.Join(trimBody(body), "\n")
+			examples = append(examples, *current)
+		}
+
+		current, body, inBody = nil, nil, false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if strings.HasPrefix(trimmed, "#") && indent == 0 {
+			flush()
+
+			name, uri := parseHeading(trimmed)
+			fields := strings.Fields(uri)
+
+			switch {
+			case strings.HasPrefix(uri, "/"):
+				inResource = name == resource || uri == resource
+				found = found || inResource
+				action, method = "", ""
+			case len(fields) > 0 && strings.ToUpper(fields[0]) == fields[0]:
+				action, method = name, fields[0]
+			default:
+				inResource = false
+			}
+
+			continue
+		}
+
+		if !inResource {
+			continue
+		}
+
+		if indent == 0 && (strings.HasPrefix(trimmed, "+ Request") || strings.HasPrefix(trimmed, "+ Response")) {
+			flush()
+
+			current = &Example{
+				Action: action,
+				Method: method,
+			}
+
+			rest := strings.TrimPrefix(trimmed, "+ ")
+			if i := strings.Index(rest, "("); i >= 0 {
+				current.ContentType = strings.Trim(rest[i:], "()")
+				rest = rest[:i]
+			}
+
+			fields := strings.Fields(rest)
+			current.Type = strings.ToLower(fields[0])
+			current.Name = strings.Join(fields[1:], " ")
+			inBody = true
+
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if trimmed != "" && indent < 8 {
+			if strings.HasPrefix(trimmed, "+") && indent > 0 {
+				inBody = strings.HasPrefix(trimmed, "+ Body")
+				continue
+			}
+
+			flush()
+			continue
+		}
+
+		if inBody {
+			body = append(body, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	flush()
+
+	if !found {
+		return nil, ErrResourceNotFound
+	}
+
+	return examples, nil
+}
+
+// parseHeading splits "Name [URI]" heading into name and bracket content,
+// headings like "/uri" have empty name
+func parseHeading(heading string) (name string, uri string) {
+	heading = strings.TrimSpace(strings.TrimLeft(heading, "#"))
+
+	i := strings.Index(heading, "[")
+	if i < 0 || !strings.HasSuffix(heading, "]") {
+		if strings.HasPrefix(heading, "/") {
+			return "", heading
+		}
+
+		return heading, ""
+	}
+
+	return strings.TrimSpace(heading[:i]), strings.TrimSpace(heading[i+1 : len(heading)-1])
+}
+
+// trimBody removes surrounding blank lines and common indentation from body
+func trimBody(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	trimmed := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+
+		trimmed = append(trimmed, strings.TrimRight(line, " \t"))
+	}
+
+	return trimmed
+}
@@ -0,0 +1,133 @@
+package apiary
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+var ExamplesBlueprint = []byte(`FORMAT: 1A
+
+# Examples
+
+## Notes [/notes]
+
+### Create [POST]
+
++ Request Note (application/json)
+
+    + Headers
+
+            X-Request-Id: 1
+
+    + Body
+
+            {
+              "title": "Hello"
+            }
+
++ Response 201
+
+## Users [/users]
+
+### List [GET]
+
++ Response 200 (application/json)
+
+        []
+`)
+
+func TestApiary_GetExamples(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	code, _ := json.Marshal(map[string]string{
+		"code": string(ValidBlueprint),
+	})
+	httpmock.RegisterNoResponder(httpmock.NewBytesResponder(200, code))
+
+	a := New(ApiaryOptions{
+		Token: Token,
+	})
+
+	t.Run("Return resource examples", func(t *testing.T) {
+		examples, err := a.GetExamples(Repository, "Accounts")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(examples) != 3 {
+			t.Fatalf("Expected 3 examples, got %d", len(examples))
+		}
+
+		e := examples[1]
+		if e.Action != "List" || e.Method != "GET" || e.Type != "response" || e.Name != "200" {
+			t.Errorf("Wrong example: %+v", e)
+		}
+
+		if e.ContentType != "application/json" || e.Body != `{"message": "Hello, world!"}` {
+			t.Errorf("Wrong example body: %+v", e)
+		}
+
+		if examples[2].Action != "Delete" || examples[2].Body != "OK" {
+			t.Errorf("Wrong example: %+v", examples[2])
+		}
+	})
+
+	t.Run("Match resource by URI", func(t *testing.T) {
+		examples, err := a.GetExamples(Repository, "/accounts")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(examples) != 3 {
+			t.Errorf("Expected 3 examples, got %d", len(examples))
+		}
+	})
+
+	t.Run("Return error on unknown resource", func(t *testing.T) {
+		_, err := a.GetExamples(Repository, "Unknown")
+
+		if err != ErrResourceNotFound {
+			t.Error("Unknown resource should return ErrResourceNotFound")
+		}
+	})
+}
+
+func Test_ParseExamples(t *testing.T) {
+	t.Run("Parse nested body sections", func(t *testing.T) {
+		examples, err := parseExamples(ExamplesBlueprint, "Notes")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(examples) != 2 {
+			t.Fatalf("Expected 2 examples, got %d", len(examples))
+		}
+
+		e := examples[0]
+		if e.Type != "request" || e.Name != "Note" || e.Method != "POST" {
+			t.Errorf("Wrong example: %+v", e)
+		}
+
+		if e.Body != "{\n  \"title\": \"Hello\"\n}" {
+			t.Errorf("Wrong example body: %q", e.Body)
+		}
+
+		if examples[1].Name != "201" || examples[1].Body != "" {
+			t.Errorf("Wrong example: %+v", examples[1])
+		}
+	})
+
+	t.Run("Stop at next resource", func(t *testing.T) {
+		examples, err := parseExamples(ExamplesBlueprint, "/users")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(examples) != 1 || examples[0].Body != "[]" {
+			t.Errorf("Wrong examples: %+v", examples)
+		}
+	})
+}
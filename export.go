@@ -0,0 +1,136 @@
+package apiary
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ArchiveVersion is a version of ExportArchive() archive layout
+const ArchiveVersion = 1
+
+// ArchiveManifest is a struct of manifest.json of ExportArchive() archive
+//
+// Description:
+// Version - archive layout version, ArchiveVersion
+// ExportedAt - time export started
+// Teams - user teams
+// Apis - exported APIs
+type ArchiveManifest struct {
+	Version    int           `json:"version"`
+	ExportedAt time.Time     `json:"exportedAt"`
+	Teams      []ApiaryTeam  `json:"teams"`
+	Apis       []ArchivedApi `json:"apis"`
+}
+
+// ArchivedApi is a struct of API in ArchiveManifest
+//
+// Description:
+// File - path of blueprint file in archive
+// FetchedAt - time blueprint was fetched
+type ArchivedApi struct {
+	CatalogEntry
+	File      string    `json:"file"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// ExportArchive writes tar.gz archive of every blueprint user can access,
+// personal and of every user team, as blueprints/<subdomain>.apib files
+// followed by manifest.json with ArchiveManifest. Blueprints are fetched and
+// streamed to w one by one. As with GetAllApis() teams which APIs couldn't be
+// listed are skipped and reported with TeamErrors once archive is written.
+func (a *Apiary) ExportArchive(w io.Writer) (err error) {
+	return a.ExportArchiveWithProgress(w, nil)
+}
+
+// ExportArchiveWithProgress writes archive as ExportArchive() does, calling
+// progress after each blueprint is written with number of blueprints done,
+// total number of blueprints and subdomain of written blueprint. Progress is
+// not reported when nil.
+func (a *Apiary) ExportArchiveWithProgress(w io.Writer, progress func(done, total int, current string)) (err error) {
+	if progress == nil {
+		progress = func(int, int, string) {}
+	}
+
+	manifest := ArchiveManifest{
+		Version:    ArchiveVersion,
+		ExportedAt: a.clock().Now().UTC(),
+		Apis:       []ArchivedApi{},
+	}
+
+	me, err := a.Me()
+	if err != nil {
+		return
+	}
+	manifest.Teams = me.Teams
+
+	entries, catalogErr := a.catalogOf(me)
+	if _, partial := catalogErr.(TeamErrors); catalogErr != nil && !partial {
+		return catalogErr
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for i, entry := range entries {
+		var blueprint *ApiaryFetchResponse
+		blueprint, err = a.FetchBlueprint(entry.Subdomain)
+		if err != nil {
+			return
+		}
+
+		api := ArchivedApi{
+			CatalogEntry: entry,
+			File:         "blueprints/" + entry.Subdomain + ".apib",
+			FetchedAt:    a.clock().Now().UTC(),
+		}
+
+		err = writeArchiveFile(tw, api.File, []byte(blueprint.Code), api.FetchedAt)
+		if err != nil {
+			return
+		}
+
+		manifest.Apis = append(manifest.Apis, api)
+		progress(i+1, len(entries), entry.Subdomain)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return
+	}
+
+	err = writeArchiveFile(tw, "manifest.json", data, manifest.ExportedAt)
+	if err != nil {
+		return
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return
+	}
+
+	err = gz.Close()
+	if err != nil {
+		return
+	}
+
+	return catalogErr
+}
+
+// writeArchiveFile writes regular file entry to tar archive
+func writeArchiveFile(tw *tar.Writer, name string, data []byte, modified time.Time) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modified,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.Write(data)
+	return err
+}
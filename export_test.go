@@ -0,0 +1,146 @@
+package apiary
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+// readArchive return files of tar.gz archive by name, in archive order
+func readArchive(t *testing.T, data []byte) (names []string, files map[string][]byte) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+
+	files = make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return
+		}
+
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		names = append(names, header.Name)
+		files[header.Name] = content
+	}
+}
+
+func TestApiary_ExportArchive(t *testing.T) {
+	t.Run("Export blueprints with manifest", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		for _, subdomain := range []string{"personal", "shared", "first", "second"} {
+			httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, subdomain), httpmock.NewStringResponder(200, fmt.Sprintf(`{"code": "# %s"}`, subdomain)))
+		}
+
+		clock := newFakeClock()
+		a := New(ApiaryOptions{
+			Token: Token,
+			Clock: clock,
+		})
+
+		buf := new(bytes.Buffer)
+		err := a.ExportArchive(buf)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		names, files := readArchive(t, buf.Bytes())
+		if len(names) != 5 || names[4] != "manifest.json" {
+			t.Fatalf("Wrong archive files: %v", names)
+		}
+
+		if string(files["blueprints/first.apib"]) != "# first" {
+			t.Errorf("Wrong blueprint: %s", files["blueprints/first.apib"])
+		}
+
+		var manifest ArchiveManifest
+		err = json.Unmarshal(files["manifest.json"], &manifest)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if manifest.Version != ArchiveVersion || !manifest.ExportedAt.Equal(clock.Now()) {
+			t.Errorf("Wrong manifest header: %+v", manifest)
+		}
+
+		if len(manifest.Teams) != 2 || manifest.Teams[0].Name != "First" {
+			t.Errorf("Wrong teams: %+v", manifest.Teams)
+		}
+
+		if len(manifest.Apis) != 4 {
+			t.Fatalf("Expected 4 APIs, got: %+v", manifest.Apis)
+		}
+
+		first := manifest.Apis[2]
+		if first.Subdomain != "first" || first.Team != "First" || first.Visibility != "private" || first.File != "blueprints/first.apib" {
+			t.Errorf("Wrong API entry: %+v", first)
+		}
+
+		if first.FetchedAt.IsZero() {
+			t.Error("Fetch time should be recorded")
+		}
+	})
+}
+
+func TestApiary_ExportArchiveWithProgress(t *testing.T) {
+	t.Run("Report progress of every blueprint", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		for _, subdomain := range []string{"personal", "shared", "first", "second"} {
+			httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, subdomain), httpmock.NewStringResponder(200, fmt.Sprintf(`{"code": "# %s"}`, subdomain)))
+		}
+
+		a := New(ApiaryOptions{Token: Token})
+
+		var calls []string
+		err := a.ExportArchiveWithProgress(new(bytes.Buffer), func(done, total int, current string) {
+			calls = append(calls, fmt.Sprintf("%d/%d %s", done, total, current))
+		})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		expected := []string{"1/4 personal", "2/4 shared", "3/4 first", "4/4 second"}
+		if fmt.Sprint(calls) != fmt.Sprint(expected) {
+			t.Errorf("Wrong progress: %v", calls)
+		}
+	})
+
+	t.Run("Nil progress", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		for _, subdomain := range []string{"personal", "shared", "first", "second"} {
+			httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, subdomain), httpmock.NewStringResponder(200, fmt.Sprintf(`{"code": "# %s"}`, subdomain)))
+		}
+
+		a := New(ApiaryOptions{Token: Token})
+		err := a.ExportArchiveWithProgress(new(bytes.Buffer), nil)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package apiary
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// utf8BOM is a byte order mark of UTF-8 text
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// FileOpts structure of blueprint file encoding options, UTF-8 with LF line
+// endings and no BOM by default
+// BOM - Start file with UTF-8 byte order mark.
+// CRLF - Use Windows (CRLF) line endings.
+type FileOpts struct {
+	BOM  bool
+	CRLF bool
+}
+
+// FetchBlueprintRange fetches raw fetch response of blueprint starting from
+// start byte, so interrupted download can be resumed. Range request is sent,
+// when server doesn't support ranges full response is downloaded and bytes
+// before start are dropped. ErrInvalidRange is returned, without making a
+// request, when start is negative.
+func (a *Apiary) FetchBlueprintRange(name string, start int64) (data []byte, err error) {
+	if start < 0 {
+		return nil, ErrInvalidRange
+	}
+
+	headers := make(map[string]string)
+	headers["Range"] = fmt.Sprintf("bytes=%d-", start)
+
+	uri := fmt.Sprintf(apiaryActionFetchBlueprint, name)
+	data, response, err := a.sendBlueprintRequest(context.Background(), uri, headers)
+	if response != nil && response.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		return []byte{}, nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+		return
+	case http.StatusOK:
+		if start >= int64(len(data)) {
+			return []byte{}, nil
+		}
+
+		return data[start:], nil
+	default:
+		return nil, checkOk(response)
+	}
+}
+
+// FetchBlueprintFull fetches blueprint and return both fetch response and
+// blueprint content
+func (a *Apiary) FetchBlueprintFull(name string) (blueprint *ApiaryFetchResponse, content []byte, err error) {
+	blueprint, err = a.FetchBlueprint(name)
+	if err != nil {
+		return
+	}
+
+	return blueprint, []byte(blueprint.Code), nil
+}
+
+// FetchBlueprintToFile fetches blueprint and writes it to file at path,
+// encoded according to opts
+func (a *Apiary) FetchBlueprintToFile(name string, path string, opts FileOpts) (err error) {
+	blueprint, err := a.FetchBlueprint(name)
+	if err != nil {
+		return
+	}
+
+	return ioutil.WriteFile(path, encodeBlueprint([]byte(blueprint.Code), opts), 0644)
+}
+
+// encodeBlueprint return blueprint content encoded according to opts
+func encodeBlueprint(content []byte, opts FileOpts) []byte {
+	content = bytes.TrimPrefix(NormalizeBlueprint(content), utf8BOM)
+	if opts.CRLF {
+		content = bytes.Replace(content, []byte("\n"), []byte("\r\n"), -1)
+	}
+
+	if opts.BOM {
+		content = append(append([]byte{}, utf8BOM...), content...)
+	}
+
+	return content
+}
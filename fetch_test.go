@@ -0,0 +1,219 @@
+package apiary
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_FetchBlueprintFull(t *testing.T) {
+	t.Run("Return response and decoded content", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, `{"error": false, "message": "", "code": "FORMAT: 1A\n# Caf\u00e9\n\n+ Body\n\n        {\"note\": \"a\\nb\"}"}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		blueprint, content, err := a.FetchBlueprintFull(Repository)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		expected := "FORMAT: 1A\n# Café\n\n+ Body\n\n        {\"note\": \"a\\nb\"}"
+		if blueprint.Error || blueprint.Code != expected {
+			t.Errorf("Wrong fetch response: %+v", blueprint)
+		}
+
+		if string(content) != expected {
+			t.Errorf("Wrong content: %q", content)
+		}
+
+		if string(content) != blueprint.Code {
+			t.Error("Content should match code of response")
+		}
+	})
+
+	t.Run("Return error on failed fetch", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(404, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		blueprint, content, err := a.FetchBlueprintFull(Repository)
+		if err == nil || blueprint != nil || content != nil {
+			t.Error("Should return Error")
+		}
+	})
+}
+
+func TestApiary_FetchBlueprintRange(t *testing.T) {
+	body := `{"code": "FORMAT: 1A"}`
+
+	t.Run("Resume with partial content", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var requested string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requested = req.Header.Get("Range")
+			return httpmock.NewStringResponse(206, body[10:]), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		data, err := a.FetchBlueprintRange(Repository, 10)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if requested != "bytes=10-" {
+			t.Errorf("Wrong Range requested: %s", requested)
+		}
+
+		if string(data) != body[10:] {
+			t.Errorf("Wrong data: %s", data)
+		}
+	})
+
+	t.Run("Fallback to full download", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, body))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		data, err := a.FetchBlueprintRange(Repository, 10)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if string(data) != body[10:] {
+			t.Errorf("Wrong data: %s", data)
+		}
+	})
+
+	t.Run("Return nothing past the end", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(416, ``))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		data, err := a.FetchBlueprintRange(Repository, 100)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(data) != 0 {
+			t.Errorf("Wrong data: %s", data)
+		}
+	})
+
+	t.Run("Return error on wrong code", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(404, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.FetchBlueprintRange(Repository, 0)
+		if err == nil {
+			t.Error("Should return Error")
+		}
+	})
+
+	t.Run("Reject negative start", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, body), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.FetchBlueprintRange(Repository, -1)
+		if err != ErrInvalidRange {
+			t.Errorf("Expected ErrInvalidRange, got: %v", err)
+		}
+
+		if requests != 0 {
+			t.Error("Request should not be made")
+		}
+	})
+}
+
+func TestApiary_FetchBlueprintToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apiary")
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	fetch := func(opts FileOpts) string {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, `{"code": "FORMAT: 1A\r\n\n# API\n"}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		path := filepath.Join(dir, "api.apib")
+		if err := a.FetchBlueprintToFile(Repository, path, opts); err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		return string(data)
+	}
+
+	for _, c := range []struct {
+		name     string
+		opts     FileOpts
+		expected string
+	}{
+		{"Write UTF-8 with LF by default", FileOpts{}, "FORMAT: 1A\n\n# API\n"},
+		{"Write UTF-8 with BOM", FileOpts{BOM: true}, "\xef\xbb\xbfFORMAT: 1A\n\n# API\n"},
+		{"Write CRLF line endings", FileOpts{CRLF: true}, "FORMAT: 1A\r\n\r\n# API\r\n"},
+		{"Write BOM and CRLF", FileOpts{BOM: true, CRLF: true}, "\xef\xbb\xbfFORMAT: 1A\r\n\r\n# API\r\n"},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			if data := fetch(c.opts); data != c.expected {
+				t.Errorf("Expected %q, got %q", c.expected, data)
+			}
+		})
+	}
+}
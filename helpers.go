@@ -2,10 +2,19 @@ package apiary
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
 )
 
 func checkOk(response *http.Response) error {
@@ -30,6 +39,19 @@ func readResponse(response *http.Response) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// newUUID return random (version 4) UUID
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
 func bearerToken(token string) string {
 	buf := bytes.NewBuffer(make([]byte, 0, len(token)+7))
 	buf.Write([]byte(`bearer `))
@@ -46,44 +68,391 @@ func bearerTokenLegacy(token string) string {
 	return buf.String()
 }
 
+func (a *Apiary) logf(format string, v ...interface{}) {
+	if a.options.Logger != nil {
+		a.options.Logger.Printf(format, v...)
+	}
+}
+
+func (a *Apiary) tokens() ([]string, error) {
+	tokens := make([]string, 0, len(a.options.Tokens)+1)
+	if a.options.Token != "" {
+		tokens = append(tokens, a.options.Token)
+	} else if a.options.CredentialStore != nil {
+		token, err := a.storedToken()
+		if err != nil {
+			return nil, err
+		}
+
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, token := range a.options.Tokens {
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	if len(tokens) == 0 {
+		tokens = append(tokens, "")
+	}
+
+	return tokens, nil
+}
+
+func unauthorized(response *http.Response) bool {
+	return response != nil &&
+		(response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden)
+}
+
+func (a *Apiary) headers(class EndpointClass, token string) map[string]string {
+	headers := make(map[string]string)
+	if a.options.UserAgent != "" {
+		headers["User-Agent"] = a.options.UserAgent
+	}
+
+	for k, v := range a.options.Headers[class] {
+		headers[k] = v
+	}
+
+	switch class {
+	case EndpointLegacy:
+		headers["Authentication"] = bearerTokenLegacy(token)
+	default:
+		headers["Authorization"] = bearerToken(token)
+	}
+
+	return headers
+}
+
+func (a *Apiary) filterApis(keep func(api ApiaryApiResponse) bool) (apis *ApiaryApisResponse, err error) {
+	all, err := a.GetApis()
+	if err != nil {
+		return
+	}
+
+	apis = &ApiaryApisResponse{
+		Apis: make([]ApiaryApiResponse, 0, len(all.Apis)),
+	}
+
+	for _, api := range all.Apis {
+		if keep(api) {
+			apis.Apis = append(apis.Apis, api)
+		}
+	}
+
+	return
+}
+
+func (a *Apiary) baseURL() string {
+	if a.options.BaseURL == "" {
+		return ApiaryAPIURL
+	}
+
+	return strings.TrimSuffix(a.options.BaseURL, "/") + "/"
+}
+
+// intercept applies RequestInterceptor and RequestSigner options to request
+func (a *Apiary) intercept(req *http.Request) error {
+	if a.options.RequestInterceptor != nil {
+		err := a.options.RequestInterceptor(req)
+		if err != nil {
+			return err
+		}
+	}
+
+	return a.sign(req)
+}
+
+// trustedURL reports whether absolute URL is on apiary.io or BaseURL host
+// and can be sent token
+func (a *Apiary) trustedURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" && u.Scheme != "http" {
+		return false
+	}
+
+	base, err := url.Parse(a.baseURL())
+	if err == nil && u.Scheme == base.Scheme && u.Host == base.Host {
+		return true
+	}
+
+	host := u.Hostname()
+	return u.Scheme == "https" && (host == "apiary.io" || strings.HasSuffix(host, ".apiary.io"))
+}
+
+func absoluteURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// parallel calls fn for every index in [0, n), no more than Parallelism
+// calls at once, and waits for them to finish
+func (a *Apiary) parallel(n int, fn func(i int)) {
+	limit := a.options.Parallelism
+	if limit <= 0 {
+		limit = DefaultParallelism
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// writable return ErrReadOnly for client in ReadOnly mode
+func (a *Apiary) writable() error {
+	if a.options.ReadOnly {
+		return ErrReadOnly
+	}
+
+	return nil
+}
+
+// policy return error of PolicyHook for mutating request, nil when hook is
+// not set
+func (a *Apiary) policy(method string, path string, body []byte) error {
+	if a.options.PolicyHook == nil {
+		return nil
+	}
+
+	return a.options.PolicyHook(method, path, body)
+}
+
+func (a *Apiary) maxPublishBytes() int64 {
+	if a.options.MaxPublishBytes > 0 {
+		return a.options.MaxPublishBytes
+	}
+
+	return DefaultMaxPublishBytes
+}
+
 func (a *Apiary) request(method string, path string, headers map[string]string, body io.Reader) (response []byte, res *http.Response, err error) {
-	url := ApiaryAPIURL + path
+	return a.requestContext(context.Background(), method, path, headers, body)
+}
+
+func (a *Apiary) requestContext(ctx context.Context, method string, path string, headers map[string]string, body io.Reader) (response []byte, res *http.Response, err error) {
+	url := path
+	if !absoluteURL(path) {
+		url = a.baseURL() + path
+	}
+
+	err = a.acquire()
+	if err != nil {
+		return
+	}
+	defer a.inflight.Done()
+
+	release, err := a.slot(ctx)
+	if err != nil {
+		return
+	}
+	defer release()
+
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return
 	}
 
+	if a.options.Trace != nil {
+		var t *tracer
+		ctx, t = withTrace(ctx)
+		defer func() {
+			a.options.Trace(path, t.done())
+		}()
+	}
+
+	if a.options.Operations != nil {
+		var done func()
+		_, ctx, done = a.options.Operations.Register(ctx)
+		defer done()
+	}
+
+	ctx, finish := a.startSpan(ctx, method, path)
+	defer func() {
+		var status int
+		if res != nil {
+			status = res.StatusCode
+		}
+
+		finish(status, err)
+	}()
+	req = req.WithContext(ctx)
+
 	for k, v := range headers {
+		if forbiddenHeader(k) {
+			continue
+		}
+
 		req.Header.Add(k, v)
 	}
 
+	// hooks are meant for apiary.io and gateway in front of it, requests
+	// to other hosts (parser service, external docs) are sent as built
+	if a.trustedURL(url) {
+		err = a.intercept(req)
+		if err != nil {
+			return
+		}
+	}
+
 	res, err = a.client.Do(req)
 	if err != nil {
+		err = transportError(err)
 		return
 	}
+	defer res.Body.Close()
+	a.recordResponse(req, res)
 
 	response, err = readResponse(res)
+	if err == nil {
+		err = verifyChecksum(res, response)
+	}
+
+	if err == nil && a.options.DumpResponsesDir != "" {
+		a.dumpResponse(path, response)
+	}
+
+	return
+}
+
+// verifyChecksum checks response body against Content-MD5 header when it is
+// present, so truncated or corrupted bodies are not accepted
+func verifyChecksum(res *http.Response, data []byte) error {
+	checksum := res.Header.Get("Content-MD5")
+	if checksum == "" {
+		return nil
+	}
+
+	sum := md5.Sum(data)
+	if base64.StdEncoding.EncodeToString(sum[:]) != checksum {
+		return ErrChecksumMismatch
+	}
+
+	return nil
+}
+
+// dumpResponse writes response body to timestamped file keyed by action in
+// DumpResponsesDir, failures are only logged
+func (a *Apiary) dumpResponse(path string, data []byte) {
+	action := strings.Trim(path, "/")
+	if i := strings.Index(action, "://"); i >= 0 {
+		action = action[i+3:]
+	}
+
+	action = strings.NewReplacer("/", "_", "?", "_", "&", "_", ":", "_").Replace(action)
+	name := fmt.Sprintf("%s-%s.json", a.clock().Now().UTC().Format("20060102T150405.000000000"), action)
+
+	err := ioutil.WriteFile(filepath.Join(a.options.DumpResponsesDir, name), data, 0644)
+	if err != nil {
+		a.logf("apiary: failed to dump response of %s: %s", path, err)
+	}
+}
+
+// send makes request authorized with configured tokens. With
+// CoalesceRequests option concurrent identical GET requests share one call.
+func (a *Apiary) send(ctx context.Context, class EndpointClass, method string, path string, extra map[string]string, body []byte) (data []byte, response *http.Response, err error) {
+	if method != "GET" && method != "HEAD" {
+		err = a.writable()
+		if err != nil {
+			return
+		}
+
+		err = a.policy(method, path, body)
+		if err != nil {
+			return
+		}
+	}
+
+	extra = callHeaders(ctx, extra)
+	if method == "GET" && a.options.CoalesceRequests {
+		return a.flights.do(ctx, flightKey(class, path, extra), func(ctx context.Context) ([]byte, *http.Response, error) {
+			return a.sendTokens(ctx, class, method, path, extra, body)
+		})
+	}
+
+	return a.sendTokens(ctx, class, method, path, extra, body)
+}
+
+// sendTokens makes request authorized with configured tokens, failing over
+// to the next token when apiary.io rejects previous one with 401/403
+func (a *Apiary) sendTokens(ctx context.Context, class EndpointClass, method string, path string, extra map[string]string, body []byte) (data []byte, response *http.Response, err error) {
+	tokens, err := a.tokens()
+	if err != nil {
+		return
+	}
+
+	for i, token := range tokens {
+		headers := a.headers(class, token)
+		for k, v := range extra {
+			headers[k] = v
+		}
+
+		data, response, err = a.do(ctx, method, path, headers, body)
+		if !unauthorized(response) {
+			if i > 0 && err == nil {
+				a.logf("apiary: %s %s succeeded with token #%d", method, path, i+1)
+			}
+
+			return
+		}
+
+		if i < len(tokens)-1 {
+			a.logf("apiary: %s %s rejected token #%d, trying next one", method, path, i+1)
+		}
+	}
+
 	return
 }
 
 func (a *Apiary) sendRequest(path string) (data []byte, response *http.Response, err error) {
-	headers := make(map[string]string)
-	headers["Authorization"] = bearerToken(a.options.Token)
-	data, response, err = a.request("GET", path, headers, nil)
+	return a.sendRequestContext(context.Background(), path)
+}
+
+func (a *Apiary) sendRequestContext(ctx context.Context, path string) (data []byte, response *http.Response, err error) {
+	data, response, err = a.send(ctx, EndpointModern, "GET", path, nil, nil)
 	return
 }
 
-func (a *Apiary) sendLegacyRequest(path string) (data []byte, response *http.Response, err error) {
-	headers := make(map[string]string)
-	headers["Authentication"] = bearerTokenLegacy(a.options.Token)
-	data, response, err = a.request("GET", path, headers, nil)
+// sendBlueprintRequest makes GET request to blueprint endpoint, trying auth
+// schemes of BlueprintAuthOrder in order while apiary.io answers with 401
+func (a *Apiary) sendBlueprintRequest(ctx context.Context, path string, extra map[string]string) (data []byte, response *http.Response, err error) {
+	order := a.options.BlueprintAuthOrder
+	if len(order) == 0 {
+		order = DefaultBlueprintAuthOrder
+	}
+
+	for i, class := range order {
+		data, response, err = a.send(ctx, class, "GET", path, extra, nil)
+		if response == nil || response.StatusCode != http.StatusUnauthorized {
+			return
+		}
+
+		if i < len(order)-1 {
+			a.logf("apiary: GET %s rejected auth scheme #%d, trying next one", path, i+1)
+		}
+	}
+
 	return
 }
 
-func (a *Apiary) sendLegacyPostRequest(path string, body io.Reader) (data []byte, response *http.Response, err error) {
-	headers := make(map[string]string)
-	headers["Authentication"] = bearerTokenLegacy(a.options.Token)
+func (a *Apiary) sendLegacyPostRequest(ctx context.Context, path string, headers map[string]string, body []byte) (data []byte, response *http.Response, err error) {
+	if headers == nil {
+		headers = make(map[string]string)
+	}
 	headers["Content-Type"] = "application/json; charset=utf-8"
-	data, response, err = a.request("POST", path, headers, body)
+	data, response, err = a.send(ctx, EndpointLegacy, "POST", path, headers, body)
 	return
 }
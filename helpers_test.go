@@ -2,13 +2,21 @@ package apiary
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"gopkg.in/jarcoal/httpmock.v1"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 //
@@ -22,6 +30,14 @@ func (r *fakeReader) Read(p []byte) (n int, err error) {
 	return 0, errors.New("OMG!")
 }
 
+type fakeLogger struct {
+	messages []string
+}
+
+func (l *fakeLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
 //
 // Test suite
 // Testing non-exported functions
@@ -88,3 +104,398 @@ func Test_Request(t *testing.T) {
 		}
 	})
 }
+
+func Test_MaxPublishBytes(t *testing.T) {
+	t.Run("Use default limit", func(t *testing.T) {
+		a := NewApiary(ApiaryOptions{})
+
+		if a.(*Apiary).maxPublishBytes() != DefaultMaxPublishBytes {
+			t.Error("Default limit should be used")
+		}
+	})
+
+	t.Run("Use configured limit", func(t *testing.T) {
+		a := NewApiary(ApiaryOptions{
+			MaxPublishBytes: 1024,
+		})
+
+		if a.(*Apiary).maxPublishBytes() != 1024 {
+			t.Error("Configured limit should be used")
+		}
+	})
+}
+
+func Test_Headers(t *testing.T) {
+	a := NewApiary(ApiaryOptions{
+		Token: "token",
+		Headers: map[EndpointClass]map[string]string{
+			EndpointModern: {"X-Modern": "1"},
+			EndpointLegacy: {"X-Legacy": "1", "Authentication": "overridden"},
+		},
+	})
+
+	t.Run("Modern endpoints headers", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var header http.Header
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			header = req.Header
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+		_, _, err := a.(*Apiary).sendRequest(apiaryActionMe)
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if header.Get("Authorization") != "bearer token" {
+			t.Error("Modern endpoints should use bearer auth")
+		}
+
+		if header.Get("X-Modern") != "1" || header.Get("X-Legacy") != "" {
+			t.Error("Modern endpoints should get only modern headers")
+		}
+	})
+
+	t.Run("Legacy endpoints headers", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var header http.Header
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			header = req.Header
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+		_, _, err := a.(*Apiary).send(context.Background(), EndpointLegacy, "GET", apiaryActionMe, nil, nil)
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if header.Get("Authentication") != "Token token" {
+			t.Error("Legacy endpoints should use token auth")
+		}
+
+		if header.Get("X-Legacy") != "1" || header.Get("X-Modern") != "" {
+			t.Error("Legacy endpoints should get only legacy headers")
+		}
+	})
+}
+
+func Test_TokensFailover(t *testing.T) {
+	t.Run("Failover to next token", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var tried []string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			tried = append(tried, req.Header.Get("Authorization"))
+			if req.Header.Get("Authorization") != "bearer backup" {
+				return httpmock.NewStringResponse(401, `{}`), nil
+			}
+
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		logger := &fakeLogger{}
+		a := NewApiary(ApiaryOptions{
+			Token:  "primary",
+			Tokens: []string{"expired", "backup"},
+			Logger: logger,
+		})
+
+		me, err := a.Me()
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if me.ID != "1" {
+			t.Error("Response of backup token should be returned")
+		}
+
+		if strings.Join(tried, ",") != "bearer primary,bearer expired,bearer backup" {
+			t.Errorf("Wrong tokens tried: %v", tried)
+		}
+
+		if len(logger.messages) == 0 || !strings.Contains(logger.messages[len(logger.messages)-1], "token #3") {
+			t.Errorf("Succeeded token should be logged: %v", logger.messages)
+		}
+	})
+
+	t.Run("Return error when all tokens fail", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(403, `{}`), nil
+		})
+
+		a := NewApiary(ApiaryOptions{
+			Token:  "primary",
+			Tokens: []string{"backup"},
+		})
+
+		_, err := a.Me()
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		if requests != 2 {
+			t.Errorf("Every token should be tried once, got %d requests", requests)
+		}
+	})
+
+	t.Run("Do not failover on other errors", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(500, `{}`), nil
+		})
+
+		a := NewApiary(ApiaryOptions{
+			Token:  "primary",
+			Tokens: []string{"backup"},
+		})
+
+		_, err := a.Me()
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		if requests != 1 {
+			t.Errorf("Only first token should be tried, got %d requests", requests)
+		}
+	})
+}
+
+func Test_BaseURL(t *testing.T) {
+	t.Run("Use public API by default", func(t *testing.T) {
+		a := NewApiary(ApiaryOptions{})
+
+		if a.(*Apiary).baseURL() != ApiaryAPIURL {
+			t.Error("Public API URL should be used")
+		}
+	})
+
+	t.Run("Use configured URL", func(t *testing.T) {
+		a := NewApiary(ApiaryOptions{
+			BaseURL: "http://localhost:8080",
+		})
+
+		if a.(*Apiary).baseURL() != "http://localhost:8080/" {
+			t.Errorf("Wrong base URL: %s", a.(*Apiary).baseURL())
+		}
+	})
+}
+
+func Test_RequestInterceptor(t *testing.T) {
+	t.Run("Modify request", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var signature string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			signature = req.Header.Get("X-Signature")
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+		a := NewApiary(ApiaryOptions{
+			Token: Token,
+			RequestInterceptor: func(req *http.Request) error {
+				req.Header.Set("X-Signature", req.Method+" "+req.URL.Path)
+				return nil
+			},
+		})
+
+		_, err := a.Me()
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if signature != "GET /me" {
+			t.Errorf("Wrong intercepted header: %s", signature)
+		}
+	})
+
+	t.Run("Abort request on error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+		interceptorErr := errors.New("Blocked")
+		a := NewApiary(ApiaryOptions{
+			Token: Token,
+			RequestInterceptor: func(req *http.Request) error {
+				return interceptorErr
+			},
+		})
+
+		_, err := a.Me()
+		if err != interceptorErr {
+			t.Errorf("Interceptor error should be returned, got %v", err)
+		}
+
+		if requests != 0 {
+			t.Error("Request should not be sent")
+		}
+	})
+}
+
+func Test_DumpResponses(t *testing.T) {
+	t.Run("Write responses when enabled", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, `{"apis": []}`))
+
+		dir, err := ioutil.TempDir("", "apiary")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		a := NewApiary(ApiaryOptions{
+			Token:            Token,
+			DumpResponsesDir: dir,
+			Clock:            newFakeClock(),
+		})
+
+		_, err = a.GetApis()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, "20170101T000000.000000000-me_apis.json"))
+		if err != nil {
+			t.Fatalf("Response should be dumped: %s", err)
+		}
+
+		if string(data) != `{"apis": []}` {
+			t.Errorf("Wrong dumped response: %s", data)
+		}
+	})
+
+	t.Run("Write nothing by default", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, `{"apis": []}`))
+
+		dir, err := ioutil.TempDir("", "apiary")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		a := NewApiary(ApiaryOptions{
+			Token: Token,
+		})
+
+		a.GetApis()
+
+		files, _ := ioutil.ReadDir(dir)
+		if len(files) != 0 {
+			t.Error("Nothing should be dumped")
+		}
+	})
+}
+
+func Test_Parallel(t *testing.T) {
+	t.Run("Bound concurrent calls", func(t *testing.T) {
+		a := NewApiary(ApiaryOptions{
+			Parallelism: 2,
+		})
+
+		var mu sync.Mutex
+		current, max, calls := 0, 0, 0
+		a.(*Apiary).parallel(10, func(i int) {
+			mu.Lock()
+			current++
+			calls++
+			if current > max {
+				max = current
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			current--
+			mu.Unlock()
+		})
+
+		if calls != 10 {
+			t.Errorf("Expected 10 calls, got %d", calls)
+		}
+
+		if max > 2 {
+			t.Errorf("Expected at most 2 concurrent calls, got %d", max)
+		}
+	})
+}
+
+func TestApiary_Checksum(t *testing.T) {
+	body := `{"code": "FORMAT: 1A\n\n# API"}`
+	sum := md5.Sum([]byte(body))
+	checksum := base64.StdEncoding.EncodeToString(sum[:])
+
+	fetch := func(bodies []string, attempts int) (calls int, err error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			response := httpmock.NewStringResponse(200, bodies[calls])
+			response.Header = http.Header{}
+			response.Header.Set("Content-MD5", checksum)
+			calls++
+
+			return response, nil
+		})
+
+		a := NewApiary(ApiaryOptions{
+			Token: Token,
+			RetryPolicy: RetryPolicy{
+				MaxAttempts: attempts,
+			},
+		})
+
+		_, err = a.FetchBlueprint(Repository)
+		return
+	}
+
+	t.Run("Accept body matching checksum", func(t *testing.T) {
+		_, err := fetch([]string{body}, 1)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+	})
+
+	t.Run("Reject corrupted body", func(t *testing.T) {
+		_, err := fetch([]string{`{"code": "FORMAT: 1A"}`}, 1)
+		if err != ErrChecksumMismatch {
+			t.Errorf("Expected ErrChecksumMismatch, got: %v", err)
+		}
+	})
+
+	t.Run("Retry corrupted body", func(t *testing.T) {
+		calls, err := fetch([]string{`{"code": "FORMAT: 1A"}`, body}, 2)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if calls != 2 {
+			t.Errorf("Corrupted body should be refetched, got %d calls", calls)
+		}
+	})
+}
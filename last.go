@@ -0,0 +1,57 @@
+package apiary
+
+import (
+	"net/http"
+	"time"
+)
+
+// ResponseInfo is a struct of answer to LastResponse() call
+//
+// Description:
+// Method - HTTP method of request
+// URL - URL of request
+// StatusCode - response HTTP status code
+// Status - response status line, like "404 Not Found"
+// Header - copy of response headers
+// ReceivedAt - time response headers were received
+type ResponseInfo struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Header     http.Header
+	ReceivedAt time.Time
+}
+
+// LastResponse return metadata of the most recent response client received,
+// for inspecting status and headers after unexpected error. Nil returned
+// when no response was received yet. Requests which failed without response
+// don't change it.
+func (a *Apiary) LastResponse() *ResponseInfo {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.last == nil {
+		return nil
+	}
+
+	last := *a.last
+	last.Header = last.Header.Clone()
+	return &last
+}
+
+// recordResponse stores metadata of response as the last one
+func (a *Apiary) recordResponse(req *http.Request, res *http.Response) {
+	info := &ResponseInfo{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		Header:     res.Header.Clone(),
+		ReceivedAt: a.clock().Now(),
+	}
+
+	a.mu.Lock()
+	a.last = info
+	a.mu.Unlock()
+}
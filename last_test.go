@@ -0,0 +1,104 @@
+package apiary
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_LastResponse(t *testing.T) {
+	t.Run("Return nil before first response", func(t *testing.T) {
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		if a.LastResponse() != nil {
+			t.Error("No response should be recorded")
+		}
+	})
+
+	t.Run("Snapshot the last call", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionMe, httpmock.NewStringResponder(200, `{}`))
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, Repository), func(req *http.Request) (*http.Response, error) {
+			response := httpmock.NewStringResponse(404, `{}`)
+			response.Status = "404 Not Found"
+			response.Header = http.Header{}
+			response.Header.Set("X-Request-Id", "r-2")
+			return response, nil
+		})
+
+		clock := newFakeClock()
+		a := New(ApiaryOptions{
+			Token: Token,
+			Clock: clock,
+		})
+
+		_, err := a.Me()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		_, err = a.FetchBlueprint(Repository)
+		if err == nil {
+			t.Fatal("Should return Error")
+		}
+
+		last := a.LastResponse()
+		if last == nil {
+			t.Fatal("Response should be recorded")
+		}
+
+		if last.Method != "GET" || last.URL != ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, Repository) {
+			t.Errorf("Wrong request: %s %s", last.Method, last.URL)
+		}
+
+		if last.StatusCode != 404 || last.Status != "404 Not Found" {
+			t.Errorf("Wrong status: %d %s", last.StatusCode, last.Status)
+		}
+
+		if last.Header.Get("X-Request-Id") != "r-2" {
+			t.Errorf("Wrong headers: %v", last.Header)
+		}
+
+		if !last.ReceivedAt.Equal(clock.Now()) {
+			t.Errorf("Wrong receive time: %s", last.ReceivedAt)
+		}
+
+		last.Header.Set("X-Request-Id", "changed")
+		if a.LastResponse().Header.Get("X-Request-Id") != "r-2" {
+			t.Error("Snapshot should be a copy")
+		}
+	})
+
+	t.Run("Record concurrent responses", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				a.Me()
+				a.LastResponse()
+			}()
+		}
+		wg.Wait()
+
+		if last := a.LastResponse(); last == nil || last.StatusCode != 200 {
+			t.Errorf("Wrong last response: %v", last)
+		}
+	})
+}
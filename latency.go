@@ -0,0 +1,85 @@
+package apiary
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// LatencyStats is a struct of answer to MeasureLatency() call
+//
+// Description:
+// Samples - number of requests measured
+// Min - shortest request duration
+// Max - longest request duration
+// Mean - mean request duration
+// P95 - 95th percentile of request duration
+type LatencyStats struct {
+	Samples int
+	Min     time.Duration
+	Max     time.Duration
+	Mean    time.Duration
+	P95     time.Duration
+}
+
+// MeasureLatency makes samples sequential me requests and return statistics
+// of their durations
+func (a *Apiary) MeasureLatency(samples int) (stats *LatencyStats, err error) {
+	return a.MeasureLatencyContext(context.Background(), samples)
+}
+
+// MeasureLatencyContext is MeasureLatency() which stops taking samples when
+// ctx is done
+func (a *Apiary) MeasureLatencyContext(ctx context.Context, samples int) (stats *LatencyStats, err error) {
+	if samples <= 0 {
+		err = errors.New("Number of samples should be positive")
+		return
+	}
+
+	durations := make([]time.Duration, 0, samples)
+	for i := 0; i < samples; i++ {
+		err = ctx.Err()
+		if err != nil {
+			return
+		}
+
+		start := a.clock().Now()
+		_, response, reqErr := a.send(ctx, EndpointModern, "GET", apiaryActionMe, nil, nil)
+		if reqErr != nil {
+			return nil, reqErr
+		}
+
+		err = checkOk(response)
+		if err != nil {
+			return
+		}
+
+		durations = append(durations, a.clock().Now().Sub(start))
+	}
+
+	return latencyStats(durations), nil
+}
+
+// latencyStats return statistics of non-empty list of durations
+func latencyStats(durations []time.Duration) *LatencyStats {
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+
+	// nearest-rank percentile
+	rank := (95*len(durations) + 99) / 100
+
+	return &LatencyStats{
+		Samples: len(durations),
+		Min:     durations[0],
+		Max:     durations[len(durations)-1],
+		Mean:    total / time.Duration(len(durations)),
+		P95:     durations[rank-1],
+	}
+}
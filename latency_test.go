@@ -0,0 +1,87 @@
+package apiary
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_MeasureLatency(t *testing.T) {
+	t.Run("Compute stats of samples", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		clock := newFakeClock()
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			// request n takes n*10ms
+			<-clock.After(time.Duration(requests) * 10 * time.Millisecond)
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			Clock: clock,
+		})
+
+		stats, err := a.MeasureLatency(20)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		expected := LatencyStats{
+			Samples: 20,
+			Min:     10 * time.Millisecond,
+			Max:     200 * time.Millisecond,
+			Mean:    105 * time.Millisecond,
+			P95:     190 * time.Millisecond,
+		}
+		if *stats != expected {
+			t.Errorf("Expected %+v, got %+v", expected, *stats)
+		}
+	})
+
+	t.Run("Stop on canceled context", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			if requests == 2 {
+				cancel()
+			}
+
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.MeasureLatencyContext(ctx, 10)
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got: %v", err)
+		}
+
+		if requests != 2 {
+			t.Errorf("Sampling should stop after cancel, got %d requests", requests)
+		}
+	})
+
+	t.Run("Reject invalid number of samples", func(t *testing.T) {
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.MeasureLatency(0)
+		if err == nil {
+			t.Error("Should return Error")
+		}
+	})
+}
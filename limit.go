@@ -0,0 +1,28 @@
+package apiary
+
+import "context"
+
+// newSlots return semaphore of MaxConcurrent request slots, nil when
+// requests are not limited
+func newSlots(limit int) chan struct{} {
+	if limit <= 0 {
+		return nil
+	}
+
+	return make(chan struct{}, limit)
+}
+
+// slot waits for free request slot with MaxConcurrent option, failing when
+// ctx is done first. Returned func releases slot.
+func (a *Apiary) slot(ctx context.Context) (release func(), err error) {
+	if a.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case a.slots <- struct{}{}:
+		return func() { <-a.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,83 @@
+package apiary
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func Test_MaxConcurrent(t *testing.T) {
+	t.Run("Limit requests in flight", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var inflight, peak int32
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			n := atomic.AddInt32(&inflight, 1)
+			defer atomic.AddInt32(&inflight, -1)
+
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:         Token,
+			MaxConcurrent: 3,
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				_, err := a.Me()
+				if err != nil {
+					t.Errorf("Error: %s", err.Error())
+				}
+			}()
+		}
+		wg.Wait()
+
+		if peak > 3 {
+			t.Errorf("No more than 3 requests should be in flight, got %d", peak)
+		}
+
+		if peak < 2 {
+			t.Errorf("Requests should run concurrently, got peak %d", peak)
+		}
+	})
+
+	t.Run("Stop waiting when context is done", func(t *testing.T) {
+		a := New(ApiaryOptions{
+			Token:         Token,
+			MaxConcurrent: 1,
+		})
+
+		release, err := a.slot(context.Background())
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = a.slot(ctx)
+		if err != context.Canceled {
+			t.Errorf("Should return context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package apiary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultLinkTimeout default timeout of a single docs link check
+const DefaultLinkTimeout = 10 * time.Second
+
+// CheckApiLinks requests docs of every API user can access and return errors
+// of docs which are not reachable or don't respond with 2xx, by API
+// subdomain. See CheckApiLinksContext().
+func (a *Apiary) CheckApiLinks() (broken map[string]error, err error) {
+	return a.CheckApiLinksContext(context.Background(), DefaultLinkTimeout)
+}
+
+// CheckApiLinksContext is CheckApiLinks() which stops when ctx is done, with
+// every link checked within timeout. Links are checked concurrently, no
+// more than Parallelism at once. Token is sent only to docs on apiary.io
+// host, so private docs are reachable for their members. As with GetAllApis()
+// links are checked along with TeamErrors when some teams fail. Once ctx is
+// done checks stop and ctx error is returned.
+func (a *Apiary) CheckApiLinksContext(ctx context.Context, timeout time.Duration) (broken map[string]error, err error) {
+	err = ctx.Err()
+	if err != nil {
+		return
+	}
+
+	entries, err := a.catalog()
+	if _, partial := err.(TeamErrors); err != nil && !partial {
+		return
+	}
+
+	broken = make(map[string]error)
+	var mu sync.Mutex
+
+	a.parallel(len(entries), func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		entry := entries[i]
+		linkErr := a.checkLink(ctx, entry.DocumentationURL, timeout)
+
+		// link is not broken when check was stopped with ctx
+		if linkErr != nil && ctx.Err() == nil {
+			mu.Lock()
+			broken[entry.Subdomain] = linkErr
+			mu.Unlock()
+		}
+	})
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return
+}
+
+// checkLink checks that docs URL responds with 2xx within timeout
+func (a *Apiary) checkLink(ctx context.Context, url string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	request := func() ([]byte, *http.Response, error) {
+		return a.do(ctx, "GET", url, nil, nil)
+	}
+
+	if a.trustedURL(url) {
+		request = func() ([]byte, *http.Response, error) {
+			return a.send(ctx, EndpointModern, "GET", url, nil, nil)
+		}
+	}
+
+	_, response, err := request()
+	if response != nil && (response.StatusCode < 200 || response.StatusCode > 299) {
+		return fmt.Errorf("Bad response code: %s", response.Status)
+	}
+
+	return err
+}
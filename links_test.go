@@ -0,0 +1,77 @@
+package apiary
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_CheckApiLinks(t *testing.T) {
+	t.Run("Report unreachable docs", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		httpmock.RegisterResponder("GET", DocumentationURL("personal"), httpmock.NewStringResponder(200, `<html>`))
+		httpmock.RegisterResponder("GET", DocumentationURL("shared"), httpmock.NewStringResponder(200, `<html>`))
+		httpmock.RegisterResponder("GET", DocumentationURL("first"), httpmock.NewStringResponder(404, `<html>`))
+		httpmock.RegisterResponder("GET", DocumentationURL("second"), func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		broken, err := a.CheckApiLinksContext(context.Background(), 50*time.Millisecond)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(broken) != 2 {
+			t.Errorf("Expected 2 broken links, got: %v", broken)
+		}
+
+		if broken["first"] == nil {
+			t.Error("Docs answering 404 should be reported")
+		}
+
+		if broken["second"] == nil {
+			t.Error("Docs not answering within timeout should be reported")
+		}
+	})
+
+	t.Run("Stop when context is done", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			cancel()
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		})
+
+		a := New(ApiaryOptions{
+			Token:       Token,
+			Parallelism: 1,
+		})
+
+		broken, err := a.CheckApiLinksContext(ctx, time.Second)
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got: %v", err)
+		}
+
+		if len(broken) != 0 {
+			t.Errorf("Canceled checks should not be reported as broken links: %v", broken)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package apiary
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// OperationManager tracks operations in flight by ID and cancels them one by
+// one. Set it as Operations option to track every client request, or
+// register own operations (like a whole PublishBlueprints batch) with
+// Register().
+type OperationManager struct {
+	mu  sync.Mutex
+	seq int
+	ops map[string]operation
+}
+
+type operation struct {
+	seq    int
+	cancel context.CancelFunc
+}
+
+// NewOperationManager creates empty OperationManager
+func NewOperationManager() *OperationManager {
+	return &OperationManager{
+		ops: make(map[string]operation),
+	}
+}
+
+// Register starts tracking operation. Operation must use returned context,
+// which is canceled by Cancel(), and call done when finished.
+func (m *OperationManager) Register(ctx context.Context) (id string, opCtx context.Context, done func()) {
+	opCtx, cancel := context.WithCancel(ctx)
+
+	m.mu.Lock()
+	m.seq++
+	id = strconv.Itoa(m.seq)
+	m.ops[id] = operation{
+		seq:    m.seq,
+		cancel: cancel,
+	}
+	m.mu.Unlock()
+
+	done = func() {
+		m.mu.Lock()
+		delete(m.ops, id)
+		m.mu.Unlock()
+
+		cancel()
+	}
+
+	return
+}
+
+// Active return IDs of operations in flight, in order of registration
+func (m *OperationManager) Active() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, len(m.ops))
+	for id := range m.ops {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return m.ops[ids[i]].seq < m.ops[ids[j]].seq
+	})
+
+	return ids
+}
+
+// Cancel cancels operation with a given ID, reporting whether it was in
+// flight
+func (m *OperationManager) Cancel(id string) bool {
+	m.mu.Lock()
+	op, ok := m.ops[id]
+	m.mu.Unlock()
+
+	if ok {
+		op.cancel()
+	}
+
+	return ok
+}
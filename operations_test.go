@@ -0,0 +1,85 @@
+package apiary
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestOperationManager(t *testing.T) {
+	t.Run("Register, list and cancel operations", func(t *testing.T) {
+		m := NewOperationManager()
+
+		first, firstCtx, firstDone := m.Register(context.Background())
+		second, secondCtx, secondDone := m.Register(context.Background())
+		defer secondDone()
+
+		active := m.Active()
+		if len(active) != 2 || active[0] != first || active[1] != second {
+			t.Fatalf("Wrong active operations: %v", active)
+		}
+
+		if !m.Cancel(first) {
+			t.Error("Operation in flight should be canceled")
+		}
+
+		if firstCtx.Err() != context.Canceled {
+			t.Error("Canceled operation context should be done")
+		}
+
+		if secondCtx.Err() != nil {
+			t.Error("Other operations should not be canceled")
+		}
+
+		firstDone()
+		if active := m.Active(); len(active) != 1 || active[0] != second {
+			t.Errorf("Finished operation should not be listed: %v", active)
+		}
+
+		if m.Cancel(first) {
+			t.Error("Finished operation should not be canceled")
+		}
+	})
+
+	t.Run("Cancel client request", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		started := make(chan struct{})
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			close(started)
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		})
+
+		m := NewOperationManager()
+		a := New(ApiaryOptions{
+			Token:      Token,
+			Operations: m,
+		})
+
+		requested := make(chan error, 1)
+		go func() {
+			_, err := a.Me()
+			requested <- err
+		}()
+		<-started
+
+		active := m.Active()
+		if len(active) != 1 {
+			t.Fatalf("Request should be tracked: %v", active)
+		}
+
+		m.Cancel(active[0])
+		if err := <-requested; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected canceled request, got: %v", err)
+		}
+
+		if len(m.Active()) != 0 {
+			t.Error("Canceled request should not be listed")
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package apiary
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Headers browsers refuse to set from fetch(), setting them under GOOS=js
+// makes request fail before it reaches apiary.io.
+var forbiddenHeaders = map[string]bool{
+	"Accept-Charset":    true,
+	"Accept-Encoding":   true,
+	"Connection":        true,
+	"Content-Length":    true,
+	"Cookie":            true,
+	"Date":              true,
+	"Host":              true,
+	"Keep-Alive":        true,
+	"Origin":            true,
+	"Referer":           true,
+	"Te":                true,
+	"Trailer":           true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+	"User-Agent":        true,
+	"Via":               true,
+}
+
+// forbiddenHeader reports whether a header can't be set on this platform
+func forbiddenHeader(key string) bool {
+	return forbiddenHeaders[http.CanonicalHeaderKey(key)]
+}
+
+// transportError adds platform specific details to transport errors.
+// Browsers hide the reason of a failed fetch(), which in most cases is
+// a CORS preflight rejected by the server.
+func transportError(err error) error {
+	msg := err.Error()
+	if strings.Contains(msg, "Failed to fetch") || strings.Contains(msg, "NetworkError") {
+		return fmt.Errorf("Request failed, probably blocked by CORS policy: %s", msg)
+	}
+
+	return err
+}
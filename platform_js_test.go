@@ -0,0 +1,39 @@
+package apiary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_ForbiddenHeader(t *testing.T) {
+	t.Run("Forbid browser controlled headers", func(t *testing.T) {
+		if !forbiddenHeader("user-agent") {
+			t.Error("User-Agent should be forbidden")
+		}
+	})
+
+	t.Run("Allow authorization headers", func(t *testing.T) {
+		if forbiddenHeader("Authorization") || forbiddenHeader("Authentication") {
+			t.Error("Authorization headers should be allowed")
+		}
+	})
+}
+
+func Test_TransportError(t *testing.T) {
+	t.Run("Explain failed fetch", func(t *testing.T) {
+		err := transportError(errors.New("net/http: fetch() failed: Failed to fetch"))
+
+		if !strings.Contains(err.Error(), "CORS") {
+			t.Error("Failed fetch should mention CORS")
+		}
+	})
+
+	t.Run("Pass other errors", func(t *testing.T) {
+		err := errors.New("OMG!")
+
+		if transportError(err) != err {
+			t.Error("Other errors should be returned as is")
+		}
+	})
+}
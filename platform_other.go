@@ -0,0 +1,14 @@
+//go:build !js
+// +build !js
+
+package apiary
+
+// forbiddenHeader reports whether a header can't be set on this platform
+func forbiddenHeader(key string) bool {
+	return false
+}
+
+// transportError adds platform specific details to transport errors
+func transportError(err error) error {
+	return err
+}
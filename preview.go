@@ -0,0 +1,124 @@
+package apiary
+
+import (
+	"bytes"
+	"strings"
+)
+
+// PublishPreview is a struct of answer to PreviewPublish() call
+//
+// Description:
+// Added - sections only in new blueprint
+// Removed - sections only in current blueprint
+// Changed - sections which content differs
+//
+// Sections are named by path of headings leading to them, joined with " > "
+// (like "Notes API > Notes [/notes] > List [GET]"), content before first
+// heading is named "". Lists are in blueprint order.
+type PublishPreview struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// HasChanges reports whether publish would change blueprint
+func (p *PublishPreview) HasChanges() bool {
+	return len(p.Added)+len(p.Removed)+len(p.Changed) > 0
+}
+
+// PreviewPublish return sections of blueprint of API publishing content would
+// add, remove or change. As apiary.io has no preview endpoint content is
+// compared with fetched current blueprint.
+func (a *Apiary) PreviewPublish(name string, content []byte) (preview *PublishPreview, err error) {
+	current, err := a.FetchBlueprint(name)
+	if err != nil {
+		return
+	}
+
+	return diffBlueprints([]byte(current.Code), content), nil
+}
+
+// blueprintSection is a section of blueprint under a heading
+type blueprintSection struct {
+	name    string
+	content string
+}
+
+// diffBlueprints return sections of next blueprint which differ from current
+func diffBlueprints(current []byte, next []byte) *PublishPreview {
+	preview := &PublishPreview{
+		Added:   []string{},
+		Removed: []string{},
+		Changed: []string{},
+	}
+
+	before := splitSections(current)
+	contents := make(map[string]string, len(before))
+	for _, section := range before {
+		contents[section.name] = section.content
+	}
+
+	after := splitSections(next)
+	kept := make(map[string]bool, len(after))
+	for _, section := range after {
+		kept[section.name] = true
+
+		content, ok := contents[section.name]
+		switch {
+		case !ok:
+			preview.Added = append(preview.Added, section.name)
+		case content != section.content:
+			preview.Changed = append(preview.Changed, section.name)
+		}
+	}
+
+	for _, section := range before {
+		if !kept[section.name] {
+			preview.Removed = append(preview.Removed, section.name)
+		}
+	}
+
+	return preview
+}
+
+// splitSections splits blueprint content into sections by headings. CRLF
+// line endings and blank lines around section content are dropped, so they
+// don't count as changes.
+func splitSections(content []byte) []blueprintSection {
+	var sections []blueprintSection
+	var path []string
+	var levels []int
+	name := ""
+	var lines []string
+
+	flush := func() {
+		body := strings.Trim(strings.Join(lines, "\n"), "\n")
+		if name != "" || body != "" {
+			sections = append(sections, blueprintSection{name: name, content: body})
+		}
+	}
+
+	for _, raw := range bytes.Split(NormalizeBlueprint(content), []byte("\n")) {
+		line := string(raw)
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+			continue
+		}
+
+		flush()
+
+		level := len(line) - len(strings.TrimLeft(line, "#"))
+		for len(levels) > 0 && levels[len(levels)-1] >= level {
+			levels = levels[:len(levels)-1]
+			path = path[:len(path)-1]
+		}
+
+		levels = append(levels, level)
+		path = append(path, strings.TrimSpace(strings.TrimLeft(line, "#")))
+		name = strings.Join(path, " > ")
+		lines = nil
+	}
+	flush()
+
+	return sections
+}
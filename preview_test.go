@@ -0,0 +1,119 @@
+package apiary
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_PreviewPublish(t *testing.T) {
+	current := strings.Join([]string{
+		"FORMAT: 1A",
+		"",
+		"# Notes API",
+		"",
+		"## Notes [/notes]",
+		"",
+		"### List [GET]",
+		"",
+		"+ Response 200",
+		"",
+		"## Tags [/tags]",
+		"",
+		"### List [GET]",
+		"",
+		"+ Response 200",
+		"",
+	}, "\r\n")
+
+	next := strings.Join([]string{
+		"FORMAT: 1A",
+		"",
+		"# Notes API",
+		"",
+		"## Notes [/notes]",
+		"",
+		"### List [GET]",
+		"",
+		"+ Response 200",
+		"",
+		"### Create [POST]",
+		"",
+		"+ Response 201",
+		"",
+		"## Users [/users]",
+		"",
+		"### List [GET]",
+		"",
+		"+ Response 204",
+	}, "\n")
+
+	t.Run("Diff against current blueprint", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		data, _ := json.Marshal(map[string]string{"code": current})
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, Repository), httpmock.NewStringResponder(200, string(data)))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		preview, err := a.PreviewPublish(Repository, []byte(next))
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if s := strings.Join(preview.Added, "|"); s != "Notes API > Notes [/notes] > Create [POST]|Notes API > Users [/users]|Notes API > Users [/users] > List [GET]" {
+			t.Errorf("Wrong added sections: %s", s)
+		}
+
+		if s := strings.Join(preview.Removed, "|"); s != "Notes API > Tags [/tags]|Notes API > Tags [/tags] > List [GET]" {
+			t.Errorf("Wrong removed sections: %s", s)
+		}
+
+		if len(preview.Changed) != 0 {
+			t.Errorf("Line endings should not be a change: %v", preview.Changed)
+		}
+
+		if !preview.HasChanges() {
+			t.Error("Preview should have changes")
+		}
+	})
+
+	t.Run("Report changed sections", func(t *testing.T) {
+		preview := diffBlueprints([]byte(next), []byte(strings.Replace(next, "+ Response 201", "+ Response 202", 1)))
+
+		if s := strings.Join(preview.Changed, "|"); s != "Notes API > Notes [/notes] > Create [POST]" {
+			t.Errorf("Wrong changed sections: %s", s)
+		}
+
+		if len(preview.Added) != 0 || len(preview.Removed) != 0 {
+			t.Errorf("Wrong preview: %v", preview)
+		}
+	})
+
+	t.Run("Diff past long lines", func(t *testing.T) {
+		long := "+ Body " + strings.Repeat("x", 70*1024)
+		old := strings.Join([]string{"# A", long, "# B", "one"}, "\n")
+		updated := strings.Join([]string{"# A", long, "# B", "two", "# C"}, "\n")
+
+		preview := diffBlueprints([]byte(old), []byte(updated))
+		if s := strings.Join(preview.Changed, "|"); s != "B" {
+			t.Errorf("Wrong changed sections: %s", s)
+		}
+
+		if s := strings.Join(preview.Added, "|"); s != "C" {
+			t.Errorf("Wrong added sections: %s", s)
+		}
+	})
+
+	t.Run("No changes for same blueprint", func(t *testing.T) {
+		if diffBlueprints([]byte(next), []byte(next)).HasChanges() {
+			t.Error("Same blueprint should have no changes")
+		}
+	})
+}
@@ -0,0 +1,309 @@
+package apiary
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DocumentFormat is a format of API description document
+type DocumentFormat string
+
+// Document formats apiary.io accepts on publish, it detects format from
+// content
+const (
+	FormatAPIBlueprint DocumentFormat = "apib"
+	FormatOpenAPI      DocumentFormat = "openapi"
+)
+
+// PublishOptions structure of possible publish options
+// IdempotencyKey - Key sent in Idempotency-Key header, so publish can be
+// safely retried. Random UUID is generated when empty.
+// Format - Format content must be in, ErrFormatMismatch is returned without
+// publishing when it is not. Not checked when empty.
+type PublishOptions struct {
+	IdempotencyKey string
+	Format         DocumentFormat
+}
+
+// DetectFormat return format of API description document, OpenAPI (Swagger)
+// documents are recognized by top level "openapi" or "swagger" field in JSON
+// or YAML, anything else is API Blueprint
+func DetectFormat(content []byte) DocumentFormat {
+	content = bytes.TrimSpace(bytes.TrimPrefix(content, utf8BOM))
+	if bytes.HasPrefix(content, []byte("{")) {
+		var document map[string]json.RawMessage
+		if json.Unmarshal(content, &document) == nil {
+			if _, ok := document["openapi"]; ok {
+				return FormatOpenAPI
+			}
+
+			if _, ok := document["swagger"]; ok {
+				return FormatOpenAPI
+			}
+		}
+
+		return FormatAPIBlueprint
+	}
+
+	for _, line := range bytes.Split(NormalizeBlueprint(content), []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("openapi:")) || bytes.HasPrefix(line, []byte("swagger:")) {
+			return FormatOpenAPI
+		}
+	}
+
+	return FormatAPIBlueprint
+}
+
+// PublishResult is a struct of answer to PublishBlueprintWithOptions() call
+//
+// Description:
+// Published - is blueprint published
+// Changed - false when publish was skipped as blueprint is unchanged, see
+// SkipUnchanged option
+// IdempotencyKey - key publish request was sent with
+// DocumentationURL - URL of published docs, from publish response when
+// present, derived from blueprint name otherwise
+type PublishResult struct {
+	Published        bool
+	Changed          bool
+	IdempotencyKey   string
+	DocumentationURL string
+}
+
+// DocumentationURL return URL of docs hosted on apiary.io for subdomain
+func DocumentationURL(subdomain string) string {
+	return fmt.Sprintf(apiaryDocumentationURL, subdomain)
+}
+
+// WarningError is an error returned when blueprint is published, but parser
+// reported warnings. Use errors.As to treat warnings as failures or ignore
+// them.
+type WarningError struct {
+	Warnings []ParserAnnotation
+}
+
+func (e *WarningError) Error() string {
+	if len(e.Warnings) == 0 {
+		return "Blueprint published with warnings"
+	}
+
+	return fmt.Sprintf("Blueprint published with %d warning(s): %s", len(e.Warnings), e.Warnings[0].Message)
+}
+
+// PublishBlueprintWithOptions publish blueprint in Apiary.io. Result is
+// returned even on error, so publish can be correlated by IdempotencyKey.
+// Content may be API Blueprint or OpenAPI document, both are sent to the same
+// endpoint and apiary.io detects format.
+// When publish response has parser warnings blueprint is published and
+// *WarningError is returned.
+//
+// Reference: http://docs.apiary.apiary.io/#reference/blueprint/publish-blueprint/get-me
+func (a *Apiary) PublishBlueprintWithOptions(name string, content []byte, opts PublishOptions) (result *PublishResult, err error) {
+	return a.publish(context.Background(), name, content, opts, nil)
+}
+
+// publish publish blueprint, canceling request when ctx is done. Progress
+// is reported with ProgressValidated and ProgressUploaded stages when set.
+func (a *Apiary) publish(ctx context.Context, name string, content []byte, opts PublishOptions, progress func(stage ProgressStage)) (result *PublishResult, err error) {
+	if progress == nil {
+		progress = func(ProgressStage) {}
+	}
+
+	result = &PublishResult{
+		IdempotencyKey: opts.IdempotencyKey,
+	}
+
+	if result.IdempotencyKey == "" {
+		result.IdempotencyKey, err = newUUID()
+		if err != nil {
+			return
+		}
+	}
+
+	if a.options.NormalizeLineEndings {
+		content = NormalizeBlueprint(content)
+	}
+
+	if int64(len(content)) > a.maxPublishBytes() {
+		err = ErrBlueprintTooLarge
+		return
+	}
+
+	if opts.Format != "" && DetectFormat(content) != opts.Format {
+		err = ErrFormatMismatch
+		return
+	}
+
+	if a.options.SkipUnchanged {
+		changed, cerr := a.blueprintChanged(ctx, name, content)
+		if cerr == nil && !changed {
+			result.DocumentationURL = DocumentationURL(name)
+			return
+		}
+
+		if cerr != nil {
+			a.logf("apiary: failed to compare blueprint of %s, publishing: %s", name, cerr)
+		}
+	}
+
+	jsonData, err := json.Marshal(map[string]string{
+		"code": string(content),
+	})
+
+	if err != nil {
+		return
+	}
+	progress(ProgressValidated)
+
+	headers := make(map[string]string)
+	headers["Idempotency-Key"] = result.IdempotencyKey
+
+	if a.options.CompressPublish {
+		jsonData, err = gzipData(jsonData)
+		if err != nil {
+			return
+		}
+
+		headers["Content-Encoding"] = "gzip"
+	}
+
+	uri := fmt.Sprintf(apiaryActionPublishBlueprint, name)
+	data, response, err := a.sendLegacyPostRequest(ctx, uri, headers, jsonData)
+	if err != nil {
+		return
+	}
+	progress(ProgressUploaded)
+
+	err = publishResponse(name, data, response, result)
+	return
+}
+
+// PublishAndGetDocsURL publish blueprint and return URL of its docs. With
+// ProbeDocsURL option docs page is requested after publish and error is
+// returned when it doesn't respond with 2xx. Blueprint published with
+// parser warnings returns URL along with *WarningError, unless probe fails.
+func (a *Apiary) PublishAndGetDocsURL(name string, content []byte) (url string, err error) {
+	result, err := a.PublishBlueprintWithOptions(name, content, PublishOptions{})
+	var warnings *WarningError
+	if err != nil && !errors.As(err, &warnings) {
+		return
+	}
+
+	url = result.DocumentationURL
+	if a.options.ProbeDocsURL {
+		if perr := a.probe(url); perr != nil {
+			err = perr
+		}
+	}
+
+	return
+}
+
+// publishSucceeded is a set of publish response statuses meaning success,
+// apiary.io answers 201 on create and 200 on update of existing API
+var publishSucceeded = map[int]bool{
+	http.StatusOK:      true,
+	http.StatusCreated: true,
+}
+
+// publishResponse checks publish response and fills result. When response
+// has parser warnings blueprint is published and *WarningError is returned.
+func publishResponse(name string, data []byte, response *http.Response, result *PublishResult) (err error) {
+	var apiaryError struct {
+		Error   bool   `json:"error"`
+		Message string `json:"message"`
+	}
+
+	// 201 body is not guaranteed to be JSON, any other answer must carry
+	// error flag
+	perr := json.Unmarshal(data, &apiaryError)
+	if perr != nil && response.StatusCode != http.StatusCreated {
+		err = perr
+		return
+	}
+
+	if apiaryError.Error {
+		err = fmt.Errorf("Creation failed: %s", apiaryError.Message)
+		return
+	}
+
+	if !publishSucceeded[response.StatusCode] {
+		err = fmt.Errorf("Bad response code: %s", response.Status)
+		return
+	}
+
+	result.Published = true
+	result.Changed = true
+	result.DocumentationURL = publishedDocumentationURL(name, data, response)
+
+	var published struct {
+		Warnings []ParserAnnotation `json:"warnings"`
+	}
+
+	if json.Unmarshal(data, &published) == nil && len(published.Warnings) > 0 {
+		for i := range published.Warnings {
+			published.Warnings[i].Type = "warning"
+		}
+
+		err = &WarningError{
+			Warnings: published.Warnings,
+		}
+	}
+
+	return
+}
+
+// probe checks that URL responds with 2xx, token is not sent
+func (a *Apiary) probe(url string) error {
+	_, response, err := a.do(context.Background(), "GET", url, nil, nil)
+	if response != nil && (response.StatusCode < 200 || response.StatusCode > 299) {
+		return fmt.Errorf("Bad response code: %s", response.Status)
+	}
+
+	return err
+}
+
+// gzipData return gzip compressed data
+func gzipData(data []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// publishedDocumentationURL return docs URL from publish response body or
+// Location header, falling back to URL derived from blueprint name
+func publishedDocumentationURL(name string, data []byte, response *http.Response) string {
+	var published struct {
+		DocumentationURL    string `json:"documentationUrl"`
+		ApiDocumentationURL string `json:"apiDocumentationUrl"`
+	}
+
+	if json.Unmarshal(data, &published) == nil {
+		if published.ApiDocumentationURL != "" {
+			return published.ApiDocumentationURL
+		}
+
+		if published.DocumentationURL != "" {
+			return published.DocumentationURL
+		}
+	}
+
+	if location := response.Header.Get("Location"); location != "" {
+		return location
+	}
+
+	return DocumentationURL(name)
+}
@@ -0,0 +1,674 @@
+package apiary
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_PublishBlueprintWithOptions(t *testing.T) {
+	t.Run("Send stable idempotency key across retries", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var keys []string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			keys = append(keys, req.Header.Get("Idempotency-Key"))
+			if len(keys) == 1 {
+				return httpmock.NewStringResponse(503, `{}`), nil
+			}
+
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			RetryPolicy: RetryPolicy{
+				MaxAttempts: 2,
+			},
+		})
+
+		r, err := a.PublishBlueprintWithOptions(Repository, ValidBlueprint, PublishOptions{})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !r.Published {
+			t.Error("Not published")
+		}
+
+		if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+			t.Errorf("Idempotency key should be stable across retries: %v", keys)
+		}
+
+		if r.IdempotencyKey != keys[0] {
+			t.Error("Sent idempotency key should be returned")
+		}
+	})
+
+	t.Run("Send caller supplied key", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var key string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			key = req.Header.Get("Idempotency-Key")
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.PublishBlueprintWithOptions(Repository, ValidBlueprint, PublishOptions{
+			IdempotencyKey: "publish-1",
+		})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if key != "publish-1" || r.IdempotencyKey != "publish-1" {
+			t.Errorf("Caller supplied key should be sent, got %s", key)
+		}
+	})
+
+	t.Run("Generate new key per call", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(201, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		first, _ := a.PublishBlueprintWithOptions(Repository, ValidBlueprint, PublishOptions{})
+		second, _ := a.PublishBlueprintWithOptions(Repository, ValidBlueprint, PublishOptions{})
+
+		if first.IdempotencyKey == second.IdempotencyKey {
+			t.Error("Each call should get its own key")
+		}
+	})
+
+}
+
+func TestApiary_PublishDocumentationURL(t *testing.T) {
+	publish := func(responder httpmock.Responder) *PublishResult {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(responder)
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.PublishBlueprintWithOptions("example", ValidBlueprint, PublishOptions{})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		return r
+	}
+
+	t.Run("Parse URL from response body", func(t *testing.T) {
+		r := publish(httpmock.NewStringResponder(201, `{"apiDocumentationUrl": "https://custom.docs.apiary.io/"}`))
+
+		if r.DocumentationURL != "https://custom.docs.apiary.io/" {
+			t.Errorf("Wrong documentation URL: %s", r.DocumentationURL)
+		}
+	})
+
+	t.Run("Parse URL from Location header", func(t *testing.T) {
+		r := publish(func(req *http.Request) (*http.Response, error) {
+			response := httpmock.NewStringResponse(201, `{}`)
+			response.Header.Set("Location", "https://location.docs.apiary.io/")
+			return response, nil
+		})
+
+		if r.DocumentationURL != "https://location.docs.apiary.io/" {
+			t.Errorf("Wrong documentation URL: %s", r.DocumentationURL)
+		}
+	})
+
+	t.Run("Derive URL from name", func(t *testing.T) {
+		r := publish(httpmock.NewStringResponder(201, `{}`))
+
+		if r.DocumentationURL != "https://example.docs.apiary.io/" {
+			t.Errorf("Wrong documentation URL: %s", r.DocumentationURL)
+		}
+	})
+}
+
+func Test_DocumentationURL(t *testing.T) {
+	if DocumentationURL("example") != "https://example.docs.apiary.io/" {
+		t.Errorf("Wrong documentation URL: %s", DocumentationURL("example"))
+	}
+}
+
+func TestApiary_PublishAndGetDocsURL(t *testing.T) {
+	docsURL := "https://example.docs.apiary.io/"
+
+	publish := func(probe bool, docsStatus int, body string) (string, int, error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		probes := 0
+		httpmock.RegisterResponder("GET", docsURL, func(req *http.Request) (*http.Response, error) {
+			probes++
+			return httpmock.NewStringResponse(docsStatus, `<html></html>`), nil
+		})
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(201, body))
+
+		a := New(ApiaryOptions{
+			Token:        Token,
+			ProbeDocsURL: probe,
+		})
+
+		url, err := a.PublishAndGetDocsURL("example", ValidBlueprint)
+		return url, probes, err
+	}
+
+	t.Run("Publish without probe", func(t *testing.T) {
+		url, probes, err := publish(false, 404, `{}`)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if url != docsURL || probes != 0 {
+			t.Errorf("Wrong URL %s or docs probed %d times", url, probes)
+		}
+	})
+
+	t.Run("Publish with reachable docs", func(t *testing.T) {
+		url, probes, err := publish(true, 200, `{}`)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if url != docsURL || probes != 1 {
+			t.Errorf("Wrong URL %s or docs probed %d times", url, probes)
+		}
+	})
+
+	t.Run("Publish with unreachable docs", func(t *testing.T) {
+		url, _, err := publish(true, 404, `{}`)
+
+		if err == nil {
+			t.Error("Unreachable docs should return error")
+		}
+
+		if url != docsURL {
+			t.Errorf("Wrong URL: %s", url)
+		}
+	})
+
+	t.Run("Publish with warnings", func(t *testing.T) {
+		url, probes, err := publish(true, 200, `{"warnings": [{"code": 6, "message": "empty response"}]}`)
+
+		var warnings *WarningError
+		if !errors.As(err, &warnings) {
+			t.Fatalf("Wrong error: %v", err)
+		}
+
+		if url != docsURL || probes != 1 {
+			t.Errorf("Wrong URL %s or docs probed %d times", url, probes)
+		}
+	})
+
+	t.Run("Publish with warnings and unreachable docs", func(t *testing.T) {
+		url, _, err := publish(true, 404, `{"warnings": [{"code": 6, "message": "empty response"}]}`)
+
+		var warnings *WarningError
+		if err == nil || errors.As(err, &warnings) {
+			t.Errorf("Probe error should be returned: %v", err)
+		}
+
+		if url != docsURL {
+			t.Errorf("Wrong URL: %s", url)
+		}
+	})
+}
+
+func TestApiary_CompressPublish(t *testing.T) {
+	t.Run("Send gzip encoded body", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var encoding string
+		var sent struct {
+			Code string `json:"code"`
+		}
+
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			encoding = req.Header.Get("Content-Encoding")
+
+			r, err := gzip.NewReader(req.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := json.NewDecoder(r).Decode(&sent); err != nil {
+				return nil, err
+			}
+
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:           Token,
+			CompressPublish: true,
+		})
+
+		published, err := a.PublishBlueprint(Repository, ValidBlueprint)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !published {
+			t.Error("Not published")
+		}
+
+		if encoding != "gzip" {
+			t.Errorf("Wrong Content-Encoding: %s", encoding)
+		}
+
+		if sent.Code != string(ValidBlueprint) {
+			t.Error("Decoded body should match original blueprint")
+		}
+	})
+}
+
+func TestApiary_PublishWarnings(t *testing.T) {
+	publish := func(body string) (bool, error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(201, body))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		return a.PublishBlueprint(Repository, ValidBlueprint)
+	}
+
+	t.Run("Return WarningError on warnings", func(t *testing.T) {
+		published, err := publish(`{"warnings": [{"code": 6, "message": "empty response"}]}`)
+
+		if !published {
+			t.Error("Blueprint with warnings should be published")
+		}
+
+		var warnings *WarningError
+		if !errors.As(err, &warnings) {
+			t.Fatalf("Should return WarningError, got %v", err)
+		}
+
+		if len(warnings.Warnings) != 1 || warnings.Warnings[0].Message != "empty response" || warnings.Warnings[0].Type != "warning" {
+			t.Errorf("Wrong warnings: %+v", warnings.Warnings)
+		}
+	})
+
+	t.Run("Return no error without warnings", func(t *testing.T) {
+		published, err := publish(`{"warnings": []}`)
+
+		if !published {
+			t.Error("Not published")
+		}
+
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+	})
+
+	t.Run("Print WarningError without warnings", func(t *testing.T) {
+		if (&WarningError{}).Error() == "" {
+			t.Error("Error message should not be empty")
+		}
+	})
+}
+
+func TestApiary_PublishStatus(t *testing.T) {
+	publish := func(status int, body string) (bool, error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(status, body))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		return a.PublishBlueprint(Repository, ValidBlueprint)
+	}
+
+	t.Run("Publish on 200", func(t *testing.T) {
+		published, err := publish(200, `{"error": false}`)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !published {
+			t.Error("Not published")
+		}
+	})
+
+	t.Run("Publish on 201", func(t *testing.T) {
+		published, err := publish(201, `{}`)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !published {
+			t.Error("Not published")
+		}
+	})
+
+	t.Run("Fail on 201 with error flag", func(t *testing.T) {
+		published, err := publish(201, `{"error": true, "message": "Invalid code"}`)
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		if published {
+			t.Error("Should not be published")
+		}
+	})
+
+	t.Run("Fail on 202", func(t *testing.T) {
+		published, err := publish(202, `{"error": false}`)
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		if published {
+			t.Error("Should not be published on unexpected status")
+		}
+	})
+}
+
+func TestApiary_ReadOnly(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	requests := 0
+	httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+	})
+
+	a := New(ApiaryOptions{
+		Token:    Token,
+		ReadOnly: true,
+	})
+
+	t.Run("Block mutating calls", func(t *testing.T) {
+		_, err := a.PublishBlueprint(Repository, ValidBlueprint)
+		if err != ErrReadOnly {
+			t.Errorf("PublishBlueprint() should return ErrReadOnly, got: %v", err)
+		}
+
+		_, err = a.PublishBlueprintReader(Repository, bytes.NewReader(ValidBlueprint))
+		if err != ErrReadOnly {
+			t.Errorf("PublishBlueprintReader() should return ErrReadOnly, got: %v", err)
+		}
+
+		_, err = a.PublishBlueprintStrict(Repository, ValidBlueprint, 0)
+		if err != ErrReadOnly {
+			t.Errorf("PublishBlueprintStrict() should return ErrReadOnly, got: %v", err)
+		}
+
+		batch := a.PublishBlueprints(context.Background(), map[string][]byte{Repository: ValidBlueprint})
+		if batch.Failed[Repository] != ErrReadOnly {
+			t.Errorf("PublishBlueprints() should return ErrReadOnly, got: %v", batch.Failed[Repository])
+		}
+
+		if requests != 0 {
+			t.Errorf("No request should be made, got %d", requests)
+		}
+	})
+
+	t.Run("Allow read calls", func(t *testing.T) {
+		_, err := a.Me()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if requests != 1 {
+			t.Errorf("Read call should make request, got %d", requests)
+		}
+	})
+}
+
+func TestApiary_PolicyHook(t *testing.T) {
+	errForbidden := errors.New("Publishing to legacy APIs is forbidden")
+	policy := func(method string, path string, body []byte) error {
+		if strings.HasSuffix(path, "/legacy-api") {
+			return errForbidden
+		}
+
+		return nil
+	}
+
+	t.Run("Block publish to disallowed API", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:      Token,
+			PolicyHook: policy,
+		})
+
+		_, err := a.PublishBlueprint("legacy-api", ValidBlueprint)
+		if err != errForbidden {
+			t.Errorf("Hook error should be returned, got: %v", err)
+		}
+
+		_, err = a.PublishBlueprintReader("legacy-api", bytes.NewReader(ValidBlueprint))
+		if err != errForbidden {
+			t.Errorf("Hook error should be returned for streamed publish, got: %v", err)
+		}
+
+		if requests != 0 {
+			t.Errorf("No request should be made, got %d", requests)
+		}
+
+		published, err := a.PublishBlueprint(Repository, ValidBlueprint)
+		if err != nil || !published {
+			t.Errorf("Allowed publish should be made, got: %v", err)
+		}
+	})
+
+	t.Run("Don't call hook for reads", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, `{}`))
+
+		called := false
+		a := New(ApiaryOptions{
+			Token: Token,
+			PolicyHook: func(method string, path string, body []byte) error {
+				called = true
+				return nil
+			},
+		})
+
+		_, err := a.Me()
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if called {
+			t.Error("Hook should be called only for mutating requests")
+		}
+	})
+}
+
+func TestApiary_SkipUnchanged(t *testing.T) {
+	mock := func(remote string, posts *int) {
+		code, _ := json.Marshal(map[string]string{"code": remote})
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, Repository), httpmock.NewBytesResponder(200, code))
+		httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, Repository), func(req *http.Request) (*http.Response, error) {
+			*posts++
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+	}
+
+	t.Run("Skip identical content", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		posts := 0
+		mock(strings.Replace(string(ValidBlueprint), "\n", "\r\n", -1), &posts)
+
+		a := New(ApiaryOptions{
+			Token:         Token,
+			SkipUnchanged: true,
+		})
+
+		r, err := a.PublishBlueprintWithOptions(Repository, ValidBlueprint, PublishOptions{})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if r.Published || r.Changed {
+			t.Errorf("Unchanged blueprint should not be published: %+v", r)
+		}
+
+		if posts != 0 {
+			t.Errorf("No publish request should be made, got %d", posts)
+		}
+	})
+
+	t.Run("Publish differing content", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		posts := 0
+		mock("FORMAT: 1A\n\n# Old\n", &posts)
+
+		a := New(ApiaryOptions{
+			Token:         Token,
+			SkipUnchanged: true,
+		})
+
+		r, err := a.PublishBlueprintWithOptions(Repository, ValidBlueprint, PublishOptions{})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !r.Published || !r.Changed {
+			t.Errorf("Changed blueprint should be published: %+v", r)
+		}
+
+		if posts != 1 {
+			t.Errorf("Publish request should be made once, got %d", posts)
+		}
+	})
+
+	t.Run("Publish when current blueprint can't be fetched", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		posts := 0
+		mock("", &posts)
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, Repository), httpmock.NewStringResponder(404, `{}`))
+
+		a := New(ApiaryOptions{
+			Token:         Token,
+			SkipUnchanged: true,
+		})
+
+		published, err := a.PublishBlueprint(Repository, ValidBlueprint)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !published || posts != 1 {
+			t.Error("Blueprint should be published")
+		}
+	})
+}
+
+func TestApiary_PublishFormat(t *testing.T) {
+	openAPIYAML := []byte("openapi: 3.0.0\ninfo:\n  title: API\n  version: '1'\npaths: {}\n")
+	openAPIJSON := []byte(`{"swagger": "2.0", "info": {"title": "API", "version": "1"}, "paths": {}}`)
+
+	publish := func(content []byte, format DocumentFormat) (string, error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var code string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			var payload struct {
+				Code string `json:"code"`
+			}
+
+			json.NewDecoder(req.Body).Decode(&payload)
+			code = payload.Code
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.PublishBlueprintWithOptions(Repository, content, PublishOptions{Format: format})
+		return code, err
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		format  DocumentFormat
+	}{
+		{"Publish API Blueprint", ValidBlueprint, FormatAPIBlueprint},
+		{"Publish OpenAPI YAML", openAPIYAML, FormatOpenAPI},
+		{"Publish Swagger JSON", openAPIJSON, FormatOpenAPI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := publish(tt.content, tt.format)
+			if err != nil {
+				t.Fatalf("Error: %s", err.Error())
+			}
+
+			if code != string(tt.content) {
+				t.Errorf("Wrong published content: %q", code)
+			}
+		})
+	}
+
+	t.Run("Reject mismatching format", func(t *testing.T) {
+		code, err := publish(ValidBlueprint, FormatOpenAPI)
+		if err != ErrFormatMismatch {
+			t.Errorf("Should return ErrFormatMismatch, got: %v", err)
+		}
+
+		if code != "" {
+			t.Error("Content should not be published")
+		}
+
+		_, err = publish(openAPIYAML, FormatAPIBlueprint)
+		if err != ErrFormatMismatch {
+			t.Errorf("Should return ErrFormatMismatch, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package apiary
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PublishQueue publishes blueprints enqueued in quick succession once per
+// API: blueprint is published after debounce interval passes without new
+// content of the same API being enqueued, and only the latest content is
+// published. Use it for noisy triggers, like file watchers, to avoid
+// needless revisions.
+type PublishQueue struct {
+	client   *Apiary
+	debounce time.Duration
+	done     func(name string, err error)
+
+	mu      sync.Mutex
+	pending map[string]*queuedPublish
+	wg      sync.WaitGroup
+}
+
+// queuedPublish is a publish waiting for debounce interval or in flight, seq
+// changes with every enqueued content
+type queuedPublish struct {
+	content []byte
+	seq     int
+}
+
+// NewPublishQueue creates PublishQueue publishing with client after debounce
+// interval. done, when set, is called with result of every publish, possibly
+// concurrently for different APIs.
+func (a *Apiary) NewPublishQueue(debounce time.Duration, done func(name string, err error)) *PublishQueue {
+	if done == nil {
+		done = func(string, error) {}
+	}
+
+	return &PublishQueue{
+		client:   a,
+		debounce: debounce,
+		done:     done,
+		pending:  make(map[string]*queuedPublish),
+	}
+}
+
+// Enqueue schedules publish of blueprint of API, replacing content enqueued
+// earlier which is not published yet. Content enqueued while blueprint of
+// API is being published is published after that publish returns, so
+// publishes of the same API never overlap.
+func (q *PublishQueue) Enqueue(name string, content []byte) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	queued, ok := q.pending[name]
+	if !ok {
+		queued = &queuedPublish{}
+		q.pending[name] = queued
+
+		q.wg.Add(1)
+		go q.wait(name, queued)
+	}
+
+	queued.content = content
+	queued.seq++
+}
+
+// Wait blocks until all enqueued blueprints are published
+func (q *PublishQueue) Wait() {
+	q.wg.Wait()
+}
+
+// wait publishes queued content once no new content is enqueued for
+// debounce interval, and keeps publishing until no content is enqueued
+// while publish is in flight
+func (q *PublishQueue) wait(name string, queued *queuedPublish) {
+	defer q.wg.Done()
+
+	for {
+		q.mu.Lock()
+		seq := queued.seq
+		q.mu.Unlock()
+
+		<-q.client.clock().After(q.debounce)
+
+		q.mu.Lock()
+		if queued.seq != seq {
+			q.mu.Unlock()
+			continue
+		}
+		content := queued.content
+		q.mu.Unlock()
+
+		_, err := q.client.publish(context.Background(), name, content, PublishOptions{}, nil)
+		q.done(name, err)
+
+		q.mu.Lock()
+		if queued.seq == seq {
+			delete(q.pending, name)
+			q.mu.Unlock()
+			return
+		}
+		q.mu.Unlock()
+	}
+}
@@ -0,0 +1,157 @@
+package apiary
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestPublishQueue(t *testing.T) {
+	mock := func() (published func() map[string][]string) {
+		var mu sync.Mutex
+		contents := make(map[string][]string)
+
+		for _, name := range []string{"first", "second"} {
+			name := name
+			httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, name), func(req *http.Request) (*http.Response, error) {
+				var payload struct {
+					Code string `json:"code"`
+				}
+				json.NewDecoder(req.Body).Decode(&payload)
+
+				mu.Lock()
+				contents[name] = append(contents[name], payload.Code)
+				mu.Unlock()
+
+				return httpmock.NewStringResponse(201, `{}`), nil
+			})
+		}
+
+		return func() map[string][]string {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return contents
+		}
+	}
+
+	t.Run("Publish only latest content", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		published := mock()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		var mu sync.Mutex
+		var errs []error
+		q := a.NewPublishQueue(30*time.Millisecond, func(name string, err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		})
+
+		q.Enqueue("first", []byte("v1"))
+		q.Enqueue("first", []byte("v2"))
+		q.Enqueue("second", []byte("s1"))
+		q.Enqueue("first", []byte("v3"))
+		q.Wait()
+
+		contents := published()
+		if len(contents["first"]) != 1 || contents["first"][0] != "v3" {
+			t.Errorf("Only latest content should be published: %q", contents["first"])
+		}
+
+		if len(contents["second"]) != 1 || contents["second"][0] != "s1" {
+			t.Errorf("Other API should be published separately: %q", contents["second"])
+		}
+
+		if len(errs) != 2 || errs[0] != nil || errs[1] != nil {
+			t.Errorf("Every publish should be reported: %v", errs)
+		}
+	})
+
+	t.Run("Publish again after interval", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		published := mock()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		q := a.NewPublishQueue(10*time.Millisecond, nil)
+
+		q.Enqueue("first", []byte("v1"))
+		q.Wait()
+		q.Enqueue("first", []byte("v2"))
+		q.Wait()
+
+		if contents := published(); len(contents["first"]) != 2 || contents["first"][1] != "v2" {
+			t.Errorf("Content enqueued after publish should be published: %q", contents["first"])
+		}
+	})
+	t.Run("Publish content enqueued during publish after it", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		started := make(chan struct{}, 2)
+		release := make(chan struct{})
+		var mu sync.Mutex
+		var contents []string
+		inFlight, overlapped := 0, false
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			var payload struct {
+				Code string `json:"code"`
+			}
+			json.NewDecoder(req.Body).Decode(&payload)
+
+			mu.Lock()
+			inFlight++
+			overlapped = overlapped || inFlight > 1
+			mu.Unlock()
+
+			started <- struct{}{}
+			<-release
+
+			mu.Lock()
+			inFlight--
+			contents = append(contents, payload.Code)
+			mu.Unlock()
+
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		q := a.NewPublishQueue(10*time.Millisecond, nil)
+
+		q.Enqueue("first", []byte("v1"))
+		<-started
+		q.Enqueue("first", []byte("v2"))
+
+		// give second publish a chance to start if it wouldn't wait
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		q.Wait()
+
+		if overlapped {
+			t.Error("Publishes of the same API should not overlap")
+		}
+
+		if strings.Join(contents, ",") != "v1,v2" {
+			t.Errorf("Latest content should be published last: %q", contents)
+		}
+	})
+}
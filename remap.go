@@ -0,0 +1,133 @@
+package apiary
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// decode decodes JSON response data into v. With FieldNames option fields
+// renamed by proxy are renamed back to apiary.io names first, only in
+// objects decoded to struct which has field of apiary.io name, so the same
+// proxy name may be used for fields of different structs.
+func (a *Apiary) decode(data []byte, v interface{}) error {
+	if len(a.options.FieldNames) == 0 {
+		return json.Unmarshal(data, v)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	err := decoder.Decode(&value)
+	if err != nil {
+		return err
+	}
+
+	remapped, err := json.Marshal(remapValue(value, reflect.TypeOf(v), a.options.FieldNames))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(remapped, v)
+}
+
+// remapValue return JSON value decoded to type t with keys of objects
+// renamed from proxy names to names of struct fields, nested values are
+// renamed by types of fields they are decoded to
+func remapValue(value interface{}, t reflect.Type, names map[string]string) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			return remapObject(v, t, names)
+		case reflect.Map:
+			for key, item := range v {
+				v[key] = remapValue(item, t.Elem(), names)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				v[i] = remapValue(item, t.Elem(), names)
+			}
+		}
+	}
+
+	return value
+}
+
+// remapObject return JSON object decoded to struct t with keys renamed to
+// names of t fields, keys which are not renamed are kept
+func remapObject(object map[string]interface{}, t reflect.Type, names map[string]string) map[string]interface{} {
+	renamed := make(map[string]interface{}, len(object))
+	used := make(map[string]bool)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+
+		source := name
+		if proxied, ok := names[name]; ok {
+			source = proxied
+		}
+
+		key, ok := objectKey(object, source)
+		if !ok {
+			continue
+		}
+
+		renamed[name] = remapValue(object[key], field.Type, names)
+		used[key] = true
+	}
+
+	for key, item := range object {
+		if _, ok := renamed[key]; !used[key] && !ok {
+			renamed[key] = item
+		}
+	}
+
+	return renamed
+}
+
+// jsonFieldName return name of struct field in JSON, "" for fields which
+// are not decoded
+func jsonFieldName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
+
+// objectKey return key of object matching name, preferring exact match and
+// falling back to case-insensitive one as encoding/json does
+func objectKey(object map[string]interface{}, name string) (string, bool) {
+	if _, ok := object[name]; ok {
+		return name, true
+	}
+
+	for key := range object {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,117 @@
+package apiary
+
+import (
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func Test_FieldNames(t *testing.T) {
+	t.Run("Decode remapped payload", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionMe, httpmock.NewStringResponder(200, `{
+			"id": "1",
+			"name": "user",
+			"teams": [{"id": "t1", "name": "First"}]
+		}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			FieldNames: map[string]string{
+				"userId":   "id",
+				"userName": "name",
+			},
+		})
+
+		me, err := a.Me()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if me.ID != "1" || me.Name != "user" {
+			t.Errorf("Wrong user: %+v", me)
+		}
+
+		if len(me.Teams) != 1 || me.Teams[0].ID != "" || me.Teams[0].Name != "" {
+			t.Errorf("Fields of teams should not be renamed: %+v", me.Teams)
+		}
+	})
+
+	t.Run("Rename fields by struct", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionMe, httpmock.NewStringResponder(200, `{
+			"id": "1",
+			"teams": [{"id": "t1", "name": "First"}]
+		}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			FieldNames: map[string]string{
+				"userId":   "id",
+				"teamId":   "id",
+				"teamName": "name",
+			},
+		})
+
+		me, err := a.Me()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if me.ID != "1" || len(me.Teams) != 1 || me.Teams[0].ID != "t1" || me.Teams[0].Name != "First" {
+			t.Errorf("Wrong user: %+v", me)
+		}
+	})
+
+	t.Run("Rename nested fields", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionGetApis, httpmock.NewStringResponder(200, `{"items": [
+			{"apiName": "First", "subdomain": "first"}
+		]}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			FieldNames: map[string]string{
+				"apis":         "items",
+				"apiSubdomain": "subdomain",
+			},
+		})
+
+		apis, err := a.GetApis()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if subdomains(apis.Apis) != "first" {
+			t.Errorf("Wrong apis: %s", subdomains(apis.Apis))
+		}
+	})
+
+	t.Run("Keep blueprint bodies", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		body := `{"code": "# API", "error": false, "id": "\u00e9"}`
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, body))
+
+		a := New(ApiaryOptions{
+			Token:      Token,
+			FieldNames: map[string]string{"userId": "id"},
+		})
+
+		data, err := a.FetchBlueprintRange(Repository, 10)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if string(data) != body[10:] {
+			t.Errorf("Raw body should not be changed: %s", data)
+		}
+	})
+}
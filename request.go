@@ -0,0 +1,57 @@
+package apiary
+
+import "context"
+
+// RequestOption is an option of a single API call, see WithHeader()
+type RequestOption func(opts *requestOptions)
+
+// requestOptions is a struct of options applied to a single API call
+type requestOptions struct {
+	headers map[string]string
+}
+
+// requestOptionsKey is a context key of per-call options
+type requestOptionsKey struct{}
+
+// WithHeader return option setting header on request of a single call,
+// overriding header configured on client
+func WithHeader(key string, value string) RequestOption {
+	return func(opts *requestOptions) {
+		opts.headers[key] = value
+	}
+}
+
+// withRequestOptions return ctx carrying per-call options
+func withRequestOptions(ctx context.Context, options []RequestOption) context.Context {
+	if len(options) == 0 {
+		return ctx
+	}
+
+	opts := &requestOptions{
+		headers: make(map[string]string),
+	}
+	for _, option := range options {
+		option(opts)
+	}
+
+	return context.WithValue(ctx, requestOptionsKey{}, opts)
+}
+
+// callHeaders return extra headers merged with per-call headers of ctx
+func callHeaders(ctx context.Context, extra map[string]string) map[string]string {
+	opts, ok := ctx.Value(requestOptionsKey{}).(*requestOptions)
+	if !ok {
+		return extra
+	}
+
+	headers := make(map[string]string, len(extra)+len(opts.headers))
+	for k, v := range extra {
+		headers[k] = v
+	}
+
+	for k, v := range opts.headers {
+		headers[k] = v
+	}
+
+	return headers
+}
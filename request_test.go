@@ -0,0 +1,78 @@
+package apiary
+
+import (
+	"net/http"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func Test_WithHeader(t *testing.T) {
+	t.Run("Apply header to single call", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var headers []http.Header
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			headers = append(headers, req.Header)
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.MeWith(WithHeader("X-Debug", "1"))
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		_, err = a.Me()
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if len(headers) != 2 {
+			t.Fatalf("Expected 2 requests, got %d", len(headers))
+		}
+
+		if headers[0].Get("X-Debug") != "1" {
+			t.Error("Per-call header should be sent")
+		}
+
+		if headers[1].Get("X-Debug") != "" {
+			t.Error("Per-call header should not be sent with next call")
+		}
+
+		if headers[0].Get("Authorization") != bearerToken(Token) {
+			t.Error("Per-call header should keep authorization")
+		}
+	})
+
+	t.Run("Override client header", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var header http.Header
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			header = req.Header
+			return httpmock.NewStringResponse(200, `{"apis": []}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			Headers: map[EndpointClass]map[string]string{
+				EndpointModern: {"X-Feature": "off"},
+			},
+		})
+
+		_, err := a.GetApisWith(WithHeader("X-Feature", "on"))
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if header.Get("X-Feature") != "on" {
+			t.Errorf("Per-call header should override client header, got %q", header.Get("X-Feature"))
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package apiary
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy structure of request retry options. Transport and server (5xx)
+// errors are retried with exponential backoff, rate limited (429) requests
+// are retried after delay server asks for in Retry-After header.
+// MaxAttempts - Maximum number of attempts, request is not retried when zero.
+// Delay - Delay before first retry of transport or server error.
+// Multiplier - Factor delay grows by with every retry of transport or server
+// error, delay is constant when not greater than 1.
+// MaxDelay - Maximum delay of transport or server error retry, unlimited
+// when zero.
+// RateLimitDelay - Delay before retry of rate limited request without
+// Retry-After header, Delay is used when zero.
+// ProxySelector - Proxy used for an attempt (starting with 1), proxy from
+// environment is used when selector returns nil.
+type RetryPolicy struct {
+	MaxAttempts    int
+	Delay          time.Duration
+	Multiplier     float64
+	MaxDelay       time.Duration
+	RateLimitDelay time.Duration
+	ProxySelector  func(attempt int) *url.URL
+}
+
+type attemptKey struct{}
+
+// attempts return number of attempts made for a request
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts > 1 {
+		return p.MaxAttempts
+	}
+
+	return 1
+}
+
+// backoff return delay before retry of attempt n (starting with 2) failed
+// with a given response
+func (p RetryPolicy) backoff(n int, response *http.Response, now time.Time) time.Duration {
+	if response != nil && response.StatusCode == http.StatusTooManyRequests {
+		if delay, ok := retryAfter(response, now); ok {
+			return delay
+		}
+
+		if p.RateLimitDelay > 0 {
+			return p.RateLimitDelay
+		}
+
+		return p.Delay
+	}
+
+	delay := p.Delay
+	if p.Multiplier > 1 {
+		delay = time.Duration(float64(delay) * math.Pow(p.Multiplier, float64(n-2)))
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	return delay
+}
+
+// retryAfter parses Retry-After header given in seconds or as HTTP date
+func retryAfter(response *http.Response, now time.Time) (time.Duration, bool) {
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if date.Before(now) {
+			return 0, true
+		}
+
+		return date.Sub(now), true
+	}
+
+	return 0, false
+}
+
+// retryable reports whether failed attempt should be retried
+func retryable(response *http.Response, err error) bool {
+	if err == ErrClientClosed {
+		return false
+	}
+
+	if response == nil || err == ErrChecksumMismatch {
+		return err != nil
+	}
+
+	return response.StatusCode == http.StatusTooManyRequests ||
+		response.StatusCode >= http.StatusInternalServerError
+}
+
+// attempt return number of attempt request belongs to
+func attempt(req *http.Request) int {
+	if n, ok := req.Context().Value(attemptKey{}).(int); ok {
+		return n
+	}
+
+	return 1
+}
+
+// do makes request retrying it according to RetryPolicy
+func (a *Apiary) do(ctx context.Context, method string, path string, headers map[string]string, body []byte) (data []byte, response *http.Response, err error) {
+	policy := a.options.RetryPolicy
+	for n := 1; n <= policy.attempts(); n++ {
+		if n > 1 {
+			a.logf("apiary: %s %s retrying, attempt #%d", method, path, n)
+
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				return
+			case <-a.clock().After(policy.backoff(n, response, a.clock().Now())):
+			}
+		}
+
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+
+		data, response, err = a.requestContext(context.WithValue(ctx, attemptKey{}, n), method, path, headers, reader)
+		if !retryable(response, err) {
+			return
+		}
+	}
+
+	return
+}
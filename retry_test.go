@@ -0,0 +1,205 @@
+package apiary
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func Test_RetryPolicy(t *testing.T) {
+	t.Run("Retry server errors", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			if requests < 3 {
+				return httpmock.NewStringResponse(503, `{}`), nil
+			}
+
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			RetryPolicy: RetryPolicy{
+				MaxAttempts: 3,
+			},
+		})
+
+		me, err := a.Me()
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if me.ID != "1" {
+			t.Error("Response of last attempt should be returned")
+		}
+
+		if requests != 3 {
+			t.Errorf("Expected 3 attempts, got %d", requests)
+		}
+	})
+
+	t.Run("Retry transport errors until attempts exhausted", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return nil, errors.New("Error")
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			RetryPolicy: RetryPolicy{
+				MaxAttempts: 2,
+			},
+		})
+
+		_, err := a.Me()
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		if requests != 2 {
+			t.Errorf("Expected 2 attempts, got %d", requests)
+		}
+	})
+
+	t.Run("Do not retry client errors", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(404, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			RetryPolicy: RetryPolicy{
+				MaxAttempts: 3,
+			},
+		})
+
+		_, err := a.Me()
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		if requests != 1 {
+			t.Errorf("Expected 1 attempt, got %d", requests)
+		}
+	})
+
+	t.Run("Do not retry by default", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(503, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		a.Me()
+
+		if requests != 1 {
+			t.Errorf("Expected 1 attempt, got %d", requests)
+		}
+	})
+}
+
+func Test_RetryBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    4,
+		Delay:          100 * time.Millisecond,
+		Multiplier:     2,
+		RateLimitDelay: 5 * time.Second,
+	}
+
+	retry := func(responses ...*http.Response) []time.Duration {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return responses[requests-1], nil
+		})
+
+		clock := newFakeClock()
+		a := New(ApiaryOptions{
+			Token:       Token,
+			RetryPolicy: policy,
+			Clock:       clock,
+		})
+
+		a.Me()
+		return clock.Delays()
+	}
+
+	ok := func() *http.Response {
+		return httpmock.NewStringResponse(200, `{"userId": "1"}`)
+	}
+
+	rateLimited := func(retryAfter string) *http.Response {
+		response := httpmock.NewStringResponse(429, `{}`)
+		response.Header = http.Header{}
+		if retryAfter != "" {
+			response.Header.Set("Retry-After", retryAfter)
+		}
+
+		return response
+	}
+
+	t.Run("Back off exponentially on server errors", func(t *testing.T) {
+		delays := retry(
+			httpmock.NewStringResponse(503, `{}`),
+			httpmock.NewStringResponse(500, `{}`),
+			httpmock.NewStringResponse(502, `{}`),
+			ok(),
+		)
+
+		expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+		if fmt.Sprint(delays) != fmt.Sprint(expected) {
+			t.Errorf("Expected delays %v, got %v", expected, delays)
+		}
+	})
+
+	t.Run("Honor Retry-After on rate limit", func(t *testing.T) {
+		// fake clock is 3s ahead after first retry
+		date := newFakeClock().Now().Add(3*time.Second + time.Minute).Format(http.TimeFormat)
+		delays := retry(rateLimited("3"), rateLimited(date), rateLimited(""), ok())
+
+		expected := []time.Duration{3 * time.Second, time.Minute, 5 * time.Second}
+		if fmt.Sprint(delays) != fmt.Sprint(expected) {
+			t.Errorf("Expected delays %v, got %v", expected, delays)
+		}
+	})
+
+	t.Run("Cap server error delay", func(t *testing.T) {
+		p := policy
+		p.MaxDelay = 150 * time.Millisecond
+
+		if d := p.backoff(2, httpmock.NewStringResponse(503, `{}`), time.Now()); d != 100*time.Millisecond {
+			t.Errorf("Wrong first delay: %v", d)
+		}
+
+		if d := p.backoff(4, httpmock.NewStringResponse(503, `{}`), time.Now()); d != 150*time.Millisecond {
+			t.Errorf("Delay should be capped, got: %v", d)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package apiary
+
+import "context"
+
+// Shutdown closes client gracefully. New requests fail with ErrClientClosed,
+// requests in flight are waited for until ctx is done, then idle connections
+// of transport created by client are closed. Shared transports, like
+// http.DefaultTransport or one of HTTPClient option, are left as is.
+func (a *Apiary) Shutdown(ctx context.Context) error {
+	a.mu.Lock()
+	a.closed = true
+	a.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		a.inflight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-drained:
+	}
+
+	if a.transport != nil {
+		a.transport.CloseIdleConnections()
+	}
+
+	return nil
+}
+
+// acquire registers request in flight, failing when client is closed
+func (a *Apiary) acquire() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return ErrClientClosed
+	}
+
+	a.inflight.Add(1)
+	return nil
+}
@@ -0,0 +1,128 @@
+package apiary
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_Shutdown(t *testing.T) {
+	t.Run("Wait for requests in flight", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			close(started)
+			<-release
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		requested := make(chan error, 1)
+		go func() {
+			_, err := a.Me()
+			requested <- err
+		}()
+		<-started
+
+		closed := make(chan error, 1)
+		go func() {
+			closed <- a.Shutdown(context.Background())
+		}()
+
+		select {
+		case <-closed:
+			t.Fatal("Shutdown should wait for request in flight")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		if err := <-requested; err != nil {
+			t.Errorf("Request in flight should succeed: %s", err.Error())
+		}
+
+		if err := <-closed; err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+	})
+
+	t.Run("Reject new requests", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, `{"userId": "1"}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		if err := a.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		_, err := a.Me()
+		if err != ErrClientClosed {
+			t.Errorf("Expected ErrClientClosed, got: %v", err)
+		}
+	})
+
+	t.Run("Stop waiting when context is done", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			close(started)
+			<-release
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		go a.Me()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if err := a.Shutdown(ctx); err != context.DeadlineExceeded {
+			t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+		}
+	})
+	t.Run("Close only transport created by client", func(t *testing.T) {
+		for _, opts := range []ApiaryOptions{
+			{Token: Token},
+			{Token: Token, Middlewares: []Middleware{func(next http.RoundTripper) http.RoundTripper { return next }}},
+			{Token: Token, HTTPClient: &http.Client{}},
+		} {
+			if a := New(opts); a.transport != nil {
+				t.Errorf("Shared transport should not be owned by client: %+v", opts)
+			}
+		}
+
+		a := New(ApiaryOptions{
+			Token:       Token,
+			DialTimeout: time.Second,
+		})
+
+		if a.transport == nil {
+			t.Fatal("Transport created by client should be owned")
+		}
+
+		if err := a.Shutdown(context.Background()); err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package apiary
+
+import (
+	"io/ioutil"
+	"net/http"
+)
+
+// RequestSigner return header with signature of request with method, path
+// (with query) and body, required by gateway in front of apiary.io
+type RequestSigner func(method string, path string, body []byte) (header string, value string, err error)
+
+// sign sets signature header of RequestSigner option on req. Body of req
+// which can't be read again, like streamed publish, is signed as nil.
+func (a *Apiary) sign(req *http.Request) error {
+	if a.options.RequestSigner == nil {
+		return nil
+	}
+
+	var body []byte
+	if req.GetBody != nil {
+		r, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		body, err = ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	header, value, err := a.options.RequestSigner(req.Method, req.URL.RequestURI(), body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(header, value)
+	return nil
+}
@@ -0,0 +1,95 @@
+package apiary
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func Test_RequestSigner(t *testing.T) {
+	signature := func(method string, path string, body []byte) string {
+		mac := hmac.New(sha256.New, []byte("gateway-secret"))
+		mac.Write([]byte(method + "\n" + path + "\n"))
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	signer := func(method string, path string, body []byte) (string, string, error) {
+		return "X-Signature", signature(method, path, body), nil
+	}
+
+	t.Run("Attach signature header", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var sent, expected []string
+		var auth string
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			var body []byte
+			if req.Body != nil {
+				body, _ = ioutil.ReadAll(req.Body)
+			}
+
+			sent = append(sent, req.Header.Get("X-Signature"))
+			expected = append(expected, signature(req.Method, req.URL.RequestURI(), body))
+			auth = req.Header.Get("Authentication")
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:         Token,
+			RequestSigner: signer,
+		})
+
+		a.Me()
+		a.PublishBlueprint(Repository, ValidBlueprint)
+
+		if len(sent) != 2 {
+			t.Fatalf("Expected 2 requests, got %d", len(sent))
+		}
+
+		for i := range sent {
+			if sent[i] == "" || sent[i] != expected[i] {
+				t.Errorf("Wrong signature of request #%d: %s", i+1, sent[i])
+			}
+		}
+
+		if auth != bearerTokenLegacy(Token) {
+			t.Error("Token auth should be kept")
+		}
+	})
+
+	t.Run("Abort request on signer error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+		errNoKey := errors.New("No signing key")
+		a := New(ApiaryOptions{
+			Token: Token,
+			RequestSigner: func(method string, path string, body []byte) (string, string, error) {
+				return "", "", errNoKey
+			},
+		})
+
+		_, err := a.Me()
+		if err != errNoKey {
+			t.Errorf("Signer error should be returned, got: %v", err)
+		}
+
+		if requests != 0 {
+			t.Errorf("No request should be made, got %d", requests)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package apiary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// flightGroup coalesces concurrent identical calls into one, like
+// golang.org/x/sync/singleflight
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is a call in flight, its result is shared by every caller
+type flightCall struct {
+	done     chan struct{}
+	waiters  int
+	cancel   context.CancelFunc
+	data     []byte
+	response *http.Response
+	err      error
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{
+		calls: make(map[string]*flightCall),
+	}
+}
+
+// do calls fn, unless call with the same key is in flight, in which case
+// its result is waited for and returned. Call is made with context detached
+// from callers, so caller giving up doesn't fail others, each caller waits
+// until its own ctx is done. Call is canceled once every caller gave up.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, *http.Response, error)) ([]byte, *http.Response, error) {
+	g.mu.Lock()
+	c, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(detachedContext{ctx})
+		c = &flightCall{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		g.calls[key] = c
+
+		go func() {
+			c.data, c.response, c.err = fn(callCtx)
+
+			g.mu.Lock()
+			g.forget(key, c)
+			g.mu.Unlock()
+
+			cancel()
+			close(c.done)
+		}()
+	}
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.data, c.response, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			g.forget(key, c)
+			c.cancel()
+		}
+		g.mu.Unlock()
+
+		return nil, nil, ctx.Err()
+	}
+}
+
+// forget removes call c from group, so next caller with key makes new call.
+// g.mu must be held.
+func (g *flightGroup) forget(key string, c *flightCall) {
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
+
+// detachedContext is a context carrying values of parent, which is never
+// canceled with parent
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// flightKey return key of request identifying it among coalesced calls
+func flightKey(class EndpointClass, path string, extra map[string]string) string {
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	key := make([]string, 0, len(keys)+1)
+	key = append(key, fmt.Sprintf("%d %s", class, path))
+	for _, k := range keys {
+		key = append(key, k+": "+extra[k])
+	}
+
+	return strings.Join(key, "\n")
+}
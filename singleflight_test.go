@@ -0,0 +1,158 @@
+package apiary
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_CoalesceRequests(t *testing.T) {
+	t.Run("Share round trip between concurrent calls", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var requests int32
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+			time.Sleep(50 * time.Millisecond)
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:            Token,
+			CoalesceRequests: true,
+		})
+
+		var wg sync.WaitGroup
+		ids := make([]string, 20)
+		for i := range ids {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				me, err := a.Me()
+				if err != nil {
+					t.Errorf("Error: %s", err.Error())
+				}
+
+				ids[i] = me.ID
+			}(i)
+		}
+		wg.Wait()
+
+		if n := atomic.LoadInt32(&requests); n != 1 {
+			t.Errorf("Expected 1 request, got %d", n)
+		}
+
+		for _, id := range ids {
+			if id != "1" {
+				t.Errorf("Every call should get shared response, got: %v", ids)
+				break
+			}
+		}
+	})
+
+	t.Run("Keep call running when first caller gives up", func(t *testing.T) {
+		g := newFlightGroup()
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		first, cancel := context.WithCancel(context.Background())
+		firstErr := make(chan error)
+		go func() {
+			_, _, err := g.do(first, "key", func(ctx context.Context) ([]byte, *http.Response, error) {
+				close(started)
+				select {
+				case <-release:
+					return []byte("shared"), nil, nil
+				case <-ctx.Done():
+					return nil, nil, ctx.Err()
+				}
+			})
+			firstErr <- err
+		}()
+		<-started
+
+		secondData := make(chan []byte)
+		go func() {
+			data, _, err := g.do(context.Background(), "key", nil)
+			if err != nil {
+				t.Errorf("Error: %s", err.Error())
+			}
+			secondData <- data
+		}()
+
+		// let second caller join call in flight
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+		if err := <-firstErr; err != context.Canceled {
+			t.Errorf("Canceled caller should get its ctx error, got: %v", err)
+		}
+
+		close(release)
+		if data := <-secondData; string(data) != "shared" {
+			t.Errorf("Waiting caller should get shared result, got: %q", data)
+		}
+	})
+
+	t.Run("Respect deadline of waiting caller", func(t *testing.T) {
+		g := newFlightGroup()
+		release := make(chan struct{})
+		defer close(release)
+
+		started := make(chan struct{})
+		go g.do(context.Background(), "key", func(ctx context.Context) ([]byte, *http.Response, error) {
+			close(started)
+			<-release
+			return nil, nil, nil
+		})
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, _, err := g.do(ctx, "key", nil)
+		if err != context.DeadlineExceeded {
+			t.Errorf("Expected deadline error, got: %v", err)
+		}
+	})
+
+	t.Run("Cancel call when every caller gave up", func(t *testing.T) {
+		g := newFlightGroup()
+		canceled := make(chan struct{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+		}()
+
+		g.do(ctx, "key", func(ctx context.Context) ([]byte, *http.Response, error) {
+			<-ctx.Done()
+			close(canceled)
+			return nil, nil, ctx.Err()
+		})
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Error("Call should be canceled")
+		}
+	})
+
+	t.Run("Keep distinct requests apart", func(t *testing.T) {
+		if flightKey(EndpointModern, apiaryActionMe, nil) == flightKey(EndpointLegacy, apiaryActionMe, nil) {
+			t.Error("Endpoint class should be part of key")
+		}
+
+		if flightKey(EndpointLegacy, "blueprint/get/api", map[string]string{"Range": "bytes=0-"}) ==
+			flightKey(EndpointLegacy, "blueprint/get/api", map[string]string{"Range": "bytes=10-"}) {
+			t.Error("Headers should be part of key")
+		}
+	})
+}
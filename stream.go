@@ -0,0 +1,145 @@
+package apiary
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+)
+
+// PublishBlueprintReader publish blueprint read from r in Apiary.io. Content
+// is streamed into request body as it is read, so it is never held in memory
+// as a whole. Streamed body can't be replayed, so request is not retried and
+// only the first configured token is used.
+//
+// Reference: http://docs.apiary.apiary.io/#reference/blueprint/publish-blueprint/get-me
+func (a *Apiary) PublishBlueprintReader(name string, r io.Reader) (published bool, err error) {
+	err = a.writable()
+	if err != nil {
+		return
+	}
+
+	uri := fmt.Sprintf(apiaryActionPublishBlueprint, name)
+	err = a.policy("POST", uri, nil)
+	if err != nil {
+		return
+	}
+
+	result := &PublishResult{}
+	result.IdempotencyKey, err = newUUID()
+	if err != nil {
+		return
+	}
+
+	tokens, err := a.tokens()
+	if err != nil {
+		return
+	}
+
+	headers := a.headers(EndpointLegacy, tokens[0])
+	headers["Content-Type"] = "application/json; charset=utf-8"
+	headers["Idempotency-Key"] = result.IdempotencyKey
+	if a.options.CompressPublish {
+		headers["Content-Encoding"] = "gzip"
+	}
+
+	pr, pw := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		err := a.writePublishBody(pw, r)
+		pw.CloseWithError(err)
+		written <- err
+	}()
+
+	data, response, err := a.requestContext(context.Background(), "POST", uri, headers, pr)
+	pr.Close()
+
+	if werr := <-written; werr != nil && werr != io.ErrClosedPipe {
+		err = werr
+	}
+
+	if err != nil {
+		return
+	}
+
+	err = publishResponse(name, data, response, result)
+	published = result.Published
+	return
+}
+
+// writePublishBody writes {"code": ...} JSON with content read from r to w,
+// compressing it with CompressPublish option
+func (a *Apiary) writePublishBody(w io.Writer, r io.Reader) (err error) {
+	if a.options.CompressPublish {
+		gz := gzip.NewWriter(w)
+		defer func() {
+			if cerr := gz.Close(); err == nil {
+				err = cerr
+			}
+		}()
+
+		w = gz
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`{"code":"`)
+
+	err = a.writeJSONString(bw, bufio.NewReader(r))
+	if err != nil {
+		return
+	}
+
+	bw.WriteString(`"}`)
+	return bw.Flush()
+}
+
+// writeJSONString writes content read from r escaped as JSON string body,
+// normalizing line endings with NormalizeLineEndings option
+func (a *Apiary) writeJSONString(w io.Writer, r *bufio.Reader) error {
+	const hex = "0123456789abcdef"
+
+	var size int64
+	esc := make([]byte, 0, 6)
+	for {
+		c, n, err := r.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		size += int64(n)
+		if size > a.maxPublishBytes() {
+			return ErrBlueprintTooLarge
+		}
+
+		if c == '\r' && a.options.NormalizeLineEndings {
+			if next, err := r.Peek(1); err == nil && next[0] == '\n' {
+				continue
+			}
+		}
+
+		esc = esc[:0]
+		switch {
+		case c == '"' || c == '\\':
+			esc = append(esc, '\\', byte(c))
+		case c == '\n':
+			esc = append(esc, `\n`...)
+		case c == '\r':
+			esc = append(esc, `\r`...)
+		case c == '\t':
+			esc = append(esc, `\t`...)
+		case c < 0x20:
+			esc = append(esc, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			esc = append(esc, string(c)...)
+		}
+
+		if _, err := w.Write(esc); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package apiary
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_PublishBlueprintReader(t *testing.T) {
+	publish := func(opts ApiaryOptions, r io.Reader) (sent string, published bool, err error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			var body io.Reader = req.Body
+			if req.Header.Get("Content-Encoding") == "gzip" {
+				gz, err := gzip.NewReader(req.Body)
+				if err != nil {
+					return nil, err
+				}
+
+				body = gz
+			}
+
+			var code struct {
+				Code string `json:"code"`
+			}
+
+			if err := json.NewDecoder(body).Decode(&code); err != nil {
+				return nil, err
+			}
+
+			sent = code.Code
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		opts.Token = Token
+		published, err = New(opts).PublishBlueprintReader(Repository, r)
+		return
+	}
+
+	t.Run("Stream blueprint from reader", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		go func() {
+			for _, chunk := range strings.SplitAfter(string(ValidBlueprint), "\n") {
+				pw.Write([]byte(chunk))
+			}
+
+			pw.Close()
+		}()
+
+		sent, published, err := publish(ApiaryOptions{}, pr)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !published {
+			t.Error("Not published")
+		}
+
+		if sent != string(ValidBlueprint) {
+			t.Errorf("Sent blueprint should match original one, got: %q", sent)
+		}
+	})
+
+	t.Run("Escape special characters", func(t *testing.T) {
+		content := "# API \"quoted\" \\ é 😀\r\n\ttab\x01"
+		sent, _, err := publish(ApiaryOptions{}, strings.NewReader(content))
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if sent != content {
+			t.Errorf("Wrong sent blueprint: %q", sent)
+		}
+	})
+
+	t.Run("Normalize and compress streamed blueprint", func(t *testing.T) {
+		sent, _, err := publish(ApiaryOptions{
+			NormalizeLineEndings: true,
+			CompressPublish:      true,
+		}, strings.NewReader("# API\r\nline\r\n"))
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if sent != "# API\nline\n" {
+			t.Errorf("Wrong sent blueprint: %q", sent)
+		}
+	})
+
+	t.Run("Stop streaming too large blueprint", func(t *testing.T) {
+		_, published, err := publish(ApiaryOptions{
+			MaxPublishBytes: 4,
+		}, strings.NewReader("# API"))
+
+		if err != ErrBlueprintTooLarge {
+			t.Errorf("Expected ErrBlueprintTooLarge, got: %v", err)
+		}
+
+		if published {
+			t.Error("Too large blueprint should not be published")
+		}
+	})
+}
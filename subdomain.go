@@ -0,0 +1,52 @@
+package apiary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// subdomainPattern is a pattern of valid DNS label used as API subdomain
+var subdomainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// reservedSubdomains is a set of subdomains apiary.io uses itself
+var reservedSubdomains = map[string]bool{
+	"api":    true,
+	"app":    true,
+	"blog":   true,
+	"docs":   true,
+	"help":   true,
+	"jsapi":  true,
+	"login":  true,
+	"mail":   true,
+	"status": true,
+	"static": true,
+	"www":    true,
+}
+
+// SubdomainAvailable checks whether API with a given subdomain can be
+// created, by requesting its docs page: 200 means subdomain is taken and 404
+// means it is free. ErrInvalidSubdomain is returned for malformed or reserved
+// subdomains.
+func (a *Apiary) SubdomainAvailable(subdomain string) (available bool, err error) {
+	if !subdomainPattern.MatchString(subdomain) || reservedSubdomains[subdomain] {
+		err = ErrInvalidSubdomain
+		return
+	}
+
+	_, response, err := a.do(context.Background(), "GET", DocumentationURL(subdomain), nil, nil)
+	if response == nil {
+		return
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return false, nil
+	case http.StatusNotFound:
+		return true, nil
+	}
+
+	err = fmt.Errorf("Bad response code: %s", response.Status)
+	return
+}
@@ -0,0 +1,60 @@
+package apiary
+
+import (
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_SubdomainAvailable(t *testing.T) {
+	available := func(status int, subdomain string) (bool, error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", DocumentationURL(subdomain), httpmock.NewStringResponder(status, `<html></html>`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		return a.SubdomainAvailable(subdomain)
+	}
+
+	t.Run("Available subdomain", func(t *testing.T) {
+		ok, err := available(404, "fresh-api")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !ok {
+			t.Error("Subdomain should be available")
+		}
+	})
+
+	t.Run("Taken subdomain", func(t *testing.T) {
+		ok, err := available(200, "taken")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if ok {
+			t.Error("Subdomain should be taken")
+		}
+	})
+
+	t.Run("Unexpected status", func(t *testing.T) {
+		_, err := available(500, "broken")
+		if err == nil {
+			t.Error("Should return Error")
+		}
+	})
+
+	t.Run("Invalid subdomains", func(t *testing.T) {
+		for _, subdomain := range []string{"", "Upper", "-dash", "dash-", "under_score", "www", "docs"} {
+			_, err := available(404, subdomain)
+			if err != ErrInvalidSubdomain {
+				t.Errorf("Expected ErrInvalidSubdomain for %q, got: %v", subdomain, err)
+			}
+		}
+	})
+}
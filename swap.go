@@ -0,0 +1,79 @@
+package apiary
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// RollbackError is an error of SwapBlueprint() call when new blueprint failed
+// and previous one couldn't be restored, API is left with unknown blueprint
+type RollbackError struct {
+	Err      error
+	Rollback error
+}
+
+func (e *RollbackError) Error() string {
+	return fmt.Sprintf("%s; rollback failed: %s", e.Err, e.Rollback)
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// SwapBlueprint replaces blueprint of API with newContent. Current blueprint
+// is fetched first, new one is published and fetched back to verify it was
+// stored. When publish or verification fails (ErrSwapVerification returned)
+// previous blueprint is republished, *RollbackError is returned when it
+// couldn't be. Blueprint published with parser warnings is not a failure.
+func (a *Apiary) SwapBlueprint(name string, newContent []byte) (err error) {
+	err = a.writable()
+	if err != nil {
+		return
+	}
+
+	current, err := a.FetchBlueprint(name)
+	if err != nil {
+		return
+	}
+
+	_, err = a.PublishBlueprint(name, newContent)
+	if !failed(err) {
+		err = a.verifyBlueprint(name, newContent)
+	}
+
+	if err == nil {
+		return
+	}
+
+	_, rollbackErr := a.PublishBlueprint(name, []byte(current.Code))
+	if failed(rollbackErr) {
+		err = &RollbackError{
+			Err:      err,
+			Rollback: rollbackErr,
+		}
+	}
+
+	return
+}
+
+// failed reports whether publish failed, blueprints published with warnings
+// are not failed
+func failed(err error) bool {
+	var warnings *WarningError
+	return err != nil && !errors.As(err, &warnings)
+}
+
+// verifyBlueprint fetches blueprint of API and checks it matches content
+func (a *Apiary) verifyBlueprint(name string, content []byte) (err error) {
+	blueprint, err := a.FetchBlueprint(name)
+	if err != nil {
+		return
+	}
+
+	if !bytes.Equal(NormalizeBlueprint([]byte(blueprint.Code)), NormalizeBlueprint(content)) {
+		err = ErrSwapVerification
+	}
+
+	return
+}
@@ -0,0 +1,149 @@
+package apiary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+// mockBlueprintStore registers responders storing published blueprint of
+// Repository, store changes stored code before it is saved
+func mockBlueprintStore(code *string, store func(code string) (string, int)) {
+	httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, Repository), func(req *http.Request) (*http.Response, error) {
+		data, _ := json.Marshal(map[string]string{"code": *code})
+		return httpmock.NewStringResponse(200, string(data)), nil
+	})
+
+	httpmock.RegisterResponder("POST", ApiaryAPIURL+fmt.Sprintf(apiaryActionPublishBlueprint, Repository), func(req *http.Request) (*http.Response, error) {
+		var payload struct {
+			Code string `json:"code"`
+		}
+
+		body, _ := ioutil.ReadAll(req.Body)
+		json.Unmarshal(body, &payload)
+
+		stored, status := store(payload.Code)
+		if status != 201 {
+			return httpmock.NewStringResponse(status, `{"error": true, "message": "Failed"}`), nil
+		}
+
+		*code = stored
+		return httpmock.NewStringResponse(201, `{}`), nil
+	})
+}
+
+func TestApiary_SwapBlueprint(t *testing.T) {
+	original := "FORMAT: 1A\n\n# Original\n"
+	updated := "FORMAT: 1A\n\n# Updated\n"
+
+	t.Run("Swap blueprint", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		code := original
+		mockBlueprintStore(&code, func(c string) (string, int) {
+			return c, 201
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		err := a.SwapBlueprint(Repository, []byte(updated))
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if code != updated {
+			t.Errorf("New blueprint should be stored, got %q", code)
+		}
+	})
+
+	t.Run("Rollback on failed verification", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		code := original
+		mockBlueprintStore(&code, func(c string) (string, int) {
+			if c == updated {
+				return c[:10], 201
+			}
+
+			return c, 201
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		err := a.SwapBlueprint(Repository, []byte(updated))
+		if err != ErrSwapVerification {
+			t.Errorf("Should return ErrSwapVerification, got %v", err)
+		}
+
+		if code != original {
+			t.Errorf("Original blueprint should be restored, got %q", code)
+		}
+	})
+
+	t.Run("Rollback on failed publish", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		code := original
+		mockBlueprintStore(&code, func(c string) (string, int) {
+			if c == updated {
+				return "", 400
+			}
+
+			return c, 201
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		err := a.SwapBlueprint(Repository, []byte(updated))
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		if code != original {
+			t.Errorf("Original blueprint should be kept, got %q", code)
+		}
+	})
+
+	t.Run("Report failed rollback", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		code := original
+		mockBlueprintStore(&code, func(c string) (string, int) {
+			if c == updated {
+				return c[:10], 201
+			}
+
+			return "", 400
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		err := a.SwapBlueprint(Repository, []byte(updated))
+
+		var rollback *RollbackError
+		if !errors.As(err, &rollback) {
+			t.Fatalf("Should return RollbackError, got %v", err)
+		}
+
+		if !errors.Is(err, ErrSwapVerification) {
+			t.Error("RollbackError should wrap verification error")
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package apiary
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// httpMethods is a set of methods allowed in action headings
+var httpMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"OPTIONS": true,
+	"TRACE":   true,
+	"CONNECT": true,
+	"LINK":    true,
+	"UNLINK":  true,
+}
+
+// CheckBlueprintSyntax checks blueprint offline for common mistakes: FORMAT
+// and HOST metadata, API name heading and resource/action headings. It is
+// not a parser, so blueprint passing it can still be invalid (MSON, payloads
+// and parameters are not checked); use ValidateBlueprint() for full
+// validation. Annotations have zero Code.
+func CheckBlueprintSyntax(content []byte) []ParserAnnotation {
+	annotations := []ParserAnnotation{}
+	annotate := func(kind string, index int, length int, format string, v ...interface{}) {
+		annotations = append(annotations, ParserAnnotation{
+			Type:     kind,
+			Message:  fmt.Sprintf(format, v...),
+			Location: []SourceRange{{Index: index, Length: length}},
+		})
+	}
+
+	inMetadata := true
+	hasFormat := false
+	hasName := false
+
+	index := 0
+	for _, raw := range bytes.SplitAfter(content, []byte("\n")) {
+		start, length := index, len(raw)
+		index += len(raw)
+
+		line := strings.TrimSpace(string(raw))
+		if line == "" {
+			continue
+		}
+
+		// headings are unindented, indented "#" lines are code blocks
+		if !strings.HasPrefix(string(raw), "#") {
+			if !inMetadata {
+				continue
+			}
+
+			i := strings.Index(line, ":")
+			if i < 0 {
+				inMetadata = false
+				continue
+			}
+
+			value := strings.TrimSpace(line[i+1:])
+			switch strings.ToUpper(strings.TrimSpace(line[:i])) {
+			case "FORMAT":
+				hasFormat = true
+				if value != "1A" {
+					annotate("error", start, length, "Unsupported format %q, expected \"1A\"", value)
+				}
+			case "HOST":
+				if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+					annotate("warning", start, length, "HOST %q is not an absolute URL", value)
+				}
+			}
+
+			continue
+		}
+
+		inMetadata = false
+		if !hasName {
+			if strings.HasPrefix(line, "# ") && strings.TrimSpace(line[2:]) != "" {
+				hasName = true
+			}
+
+			continue
+		}
+
+		checkHeading(line, func(format string, v ...interface{}) {
+			annotate("error", start, length, format, v...)
+		})
+	}
+
+	if !hasFormat {
+		annotate("warning", 0, 0, "Missing FORMAT: 1A metadata")
+	}
+
+	if !hasName {
+		annotate("error", 0, 0, "Expected API name heading (\"# <API name>\")")
+	}
+
+	return annotations
+}
+
+// checkHeading checks resource or action heading like "## Name [GET /uri]",
+// only trailing brackets are checked as heading text may have links
+func checkHeading(line string, fail func(format string, v ...interface{})) {
+	if !strings.HasSuffix(line, "]") {
+		return
+	}
+
+	open := strings.LastIndex(line, "[")
+	if open < 0 {
+		return
+	}
+
+	fields := strings.Fields(line[open+1 : len(line)-1])
+	if len(fields) == 0 {
+		fail("Empty brackets in heading %q", line)
+		return
+	}
+
+	uri := fields[len(fields)-1]
+	if len(fields) == 2 || !isURITemplate(uri) {
+		method := fields[0]
+		if !httpMethods[method] {
+			fail("Unknown HTTP method %q", method)
+		}
+	}
+
+	if len(fields) > 2 {
+		fail("Unexpected %q in heading brackets", strings.Join(fields, " "))
+	} else if len(fields) == 2 && !isURITemplate(uri) {
+		fail("Invalid URI template %q", uri)
+	}
+}
+
+// isURITemplate reports whether s looks like URI template of resource
+func isURITemplate(s string) bool {
+	return strings.HasPrefix(s, "/") || strings.HasPrefix(s, "{")
+}
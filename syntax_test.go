@@ -0,0 +1,60 @@
+package apiary
+
+import (
+	"testing"
+)
+
+func Test_CheckBlueprintSyntax(t *testing.T) {
+	t.Run("Pass valid blueprint", func(t *testing.T) {
+		annotations := CheckBlueprintSyntax(ValidBlueprint)
+		if len(annotations) != 0 {
+			t.Errorf("No annotations expected, got: %+v", annotations)
+		}
+	})
+
+	t.Run("Skip indented lines", func(t *testing.T) {
+		annotations := CheckBlueprintSyntax([]byte("FORMAT: 1A\n# API\n\n    # comment [FETCH]\n"))
+		if len(annotations) != 0 {
+			t.Errorf("No annotations expected, got: %+v", annotations)
+		}
+	})
+
+	t.Run("Skip links in headings", func(t *testing.T) {
+		annotations := CheckBlueprintSyntax([]byte("FORMAT: 1A\n# API\n## Docs [guide](https://example.com/guide)\n"))
+		if len(annotations) != 0 {
+			t.Errorf("No annotations expected, got: %+v", annotations)
+		}
+	})
+
+	for _, c := range []struct {
+		name    string
+		content string
+		kind    string
+		message string
+		index   int
+	}{
+		{"Warn on missing FORMAT", "# API\n", "warning", "Missing FORMAT: 1A metadata", 0},
+		{"Fail on unsupported FORMAT", "FORMAT: 1B\n# API\n", "error", `Unsupported format "1B", expected "1A"`, 0},
+		{"Warn on invalid HOST", "FORMAT: 1A\nHOST: api\n# API\n", "warning", `HOST "api" is not an absolute URL`, 11},
+		{"Fail without API name", "FORMAT: 1A\n\nSome text\n", "error", `Expected API name heading ("# <API name>")`, 0},
+		{"Fail on unknown method", "FORMAT: 1A\n# API\n## Users [/users]\n### List [FETCH]\n", "error", `Unknown HTTP method "FETCH"`, 35},
+		{"Fail on invalid URI", "FORMAT: 1A\n# API\n### Get [GET users]\n", "error", `Invalid URI template "users"`, 17},
+	} {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			annotations := CheckBlueprintSyntax([]byte(c.content))
+			if len(annotations) != 1 {
+				t.Fatalf("Expected 1 annotation, got: %+v", annotations)
+			}
+
+			a := annotations[0]
+			if a.Type != c.kind || a.Message != c.message {
+				t.Errorf("Wrong annotation: %+v", a)
+			}
+
+			if len(a.Location) != 1 || a.Location[0].Index != c.index {
+				t.Errorf("Wrong location: %+v", a.Location)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package apiary
+
+// ResolveTeamID return ID of user team by its name, IDs are returned
+// unchanged. ErrTeamNotFound returned when user has no such team.
+func (a *Apiary) ResolveTeamID(nameOrID string) (id string, err error) {
+	me, err := a.Me()
+	if err != nil {
+		return
+	}
+
+	for _, team := range me.Teams {
+		if team.ID == nameOrID {
+			return team.ID, nil
+		}
+	}
+
+	for _, team := range me.Teams {
+		if team.Name == nameOrID {
+			return team.ID, nil
+		}
+	}
+
+	err = ErrTeamNotFound
+	return
+}
+
+// DefaultTeam return team configured with DefaultTeam option, or first user
+// team when option is empty. ErrNoTeams returned when user has no teams and
+// ErrTeamNotFound when configured team is not one of them.
+func (a *Apiary) DefaultTeam() (team *ApiaryTeam, err error) {
+	me, err := a.Me()
+	if err != nil {
+		return
+	}
+
+	if len(me.Teams) == 0 {
+		err = ErrNoTeams
+		return
+	}
+
+	preferred := a.options.DefaultTeam
+	if preferred == "" {
+		return &me.Teams[0], nil
+	}
+
+	for i := range me.Teams {
+		if me.Teams[i].ID == preferred || me.Teams[i].Name == preferred {
+			return &me.Teams[i], nil
+		}
+	}
+
+	err = ErrTeamNotFound
+	return
+}
+
+// IsMemberOf reports whether user is a member of team given by ID or name.
+// Teams user is not a member of are not visible, so unknown team is reported
+// as non-membership with nil error.
+func (a *Apiary) IsMemberOf(team string) (member bool, err error) {
+	_, err = a.ResolveTeamID(team)
+	if err == ErrTeamNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
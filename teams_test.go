@@ -0,0 +1,186 @@
+package apiary
+
+import (
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_ResolveTeamID(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	mockCatalog()
+
+	a := New(ApiaryOptions{
+		Token: Token,
+	})
+
+	t.Run("Map name to ID", func(t *testing.T) {
+		id, err := a.ResolveTeamID("Second")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if id != "t2" {
+			t.Errorf("Wrong team ID: %s", id)
+		}
+	})
+
+	t.Run("Pass ID through", func(t *testing.T) {
+		id, err := a.ResolveTeamID("t1")
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if id != "t1" {
+			t.Errorf("Wrong team ID: %s", id)
+		}
+	})
+
+	t.Run("Return error on unknown team", func(t *testing.T) {
+		_, err := a.ResolveTeamID("Unknown")
+
+		if err != ErrTeamNotFound {
+			t.Error("Unknown team should return ErrTeamNotFound")
+		}
+	})
+}
+
+func TestApiary_IsMemberOf(t *testing.T) {
+	t.Run("Member by ID and name", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		for _, team := range []string{"t1", "Second"} {
+			member, err := a.IsMemberOf(team)
+			if err != nil {
+				t.Fatalf("Error: %s", err.Error())
+			}
+
+			if !member {
+				t.Errorf("User should be member of %s", team)
+			}
+		}
+	})
+
+	t.Run("Not a member", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		for _, team := range []string{"t3", "Unknown"} {
+			member, err := a.IsMemberOf(team)
+			if err != nil {
+				t.Fatalf("Non-membership should not be an error: %s", err.Error())
+			}
+
+			if member {
+				t.Errorf("User should not be member of %s", team)
+			}
+		}
+	})
+
+	t.Run("Return error when user can't be fetched", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(500, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		member, err := a.IsMemberOf("t1")
+		if err == nil || member {
+			t.Error("Should return Error")
+		}
+	})
+}
+
+func TestApiary_DefaultTeam(t *testing.T) {
+	t.Run("Return first team", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		team, err := a.DefaultTeam()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if team.ID != "t1" || team.Name != "First" {
+			t.Errorf("Wrong team: %+v", team)
+		}
+	})
+
+	t.Run("Return preferred team", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		a := New(ApiaryOptions{
+			Token:       Token,
+			DefaultTeam: "Second",
+		})
+
+		team, err := a.DefaultTeam()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if team.ID != "t2" {
+			t.Errorf("Wrong team: %+v", team)
+		}
+	})
+
+	t.Run("Return error on unknown preferred team", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+
+		a := New(ApiaryOptions{
+			Token:       Token,
+			DefaultTeam: "Unknown",
+		})
+
+		_, err := a.DefaultTeam()
+		if err != ErrTeamNotFound {
+			t.Error("Should return ErrTeamNotFound")
+		}
+	})
+
+	t.Run("Return error without teams", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, `{"userId": "1", "teams": []}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.DefaultTeam()
+		if err != ErrNoTeams {
+			t.Error("Should return ErrNoTeams")
+		}
+	})
+}
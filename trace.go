@@ -0,0 +1,89 @@
+package apiary
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// TraceTiming is a struct of request phase timings reported to Trace option.
+// Phases which didn't happen (like DNS lookup of reused connection) are zero.
+//
+// Description:
+// DNSLookup - DNS lookup duration
+// Connect - TCP connection setup duration
+// TLSHandshake - TLS handshake duration
+// FirstByte - time from request start to first response byte
+// Total - time from request start to reading whole response
+type TraceTiming struct {
+	DNSLookup    time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration
+	Total        time.Duration
+}
+
+// tracer collects timings of a single request
+type tracer struct {
+	mu                                      sync.Mutex
+	start, dnsStart, connectStart, tlsStart time.Time
+	timing                                  TraceTiming
+}
+
+// withTrace attaches client trace collecting timings to ctx
+func withTrace(ctx context.Context) (context.Context, *tracer) {
+	t := &tracer{
+		start: time.Now(),
+	}
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dnsStart = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			t.timing.DNSLookup = time.Since(t.dnsStart)
+			t.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			t.connectStart = time.Now()
+			t.mu.Unlock()
+		},
+		ConnectDone: func(string, string, error) {
+			t.mu.Lock()
+			t.timing.Connect = time.Since(t.connectStart)
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tlsStart = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			t.timing.TLSHandshake = time.Since(t.tlsStart)
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.timing.FirstByte = time.Since(t.start)
+			t.mu.Unlock()
+		},
+	}
+
+	return httptrace.WithClientTrace(ctx, trace), t
+}
+
+// done return collected timings
+func (t *tracer) done() TraceTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timing.Total = time.Since(t.start)
+	return t.timing
+}
@@ -0,0 +1,48 @@
+package apiary
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_Trace(t *testing.T) {
+	t.Run("Report request timings", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"userId": "1"}`))
+		}))
+		defer server.Close()
+
+		var paths []string
+		var timing TraceTiming
+		a := New(ApiaryOptions{
+			Token:   Token,
+			BaseURL: server.URL,
+			Trace: func(path string, t TraceTiming) {
+				paths = append(paths, path)
+				timing = t
+			},
+		})
+
+		_, err := a.Me()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(paths) != 1 || paths[0] != apiaryActionMe {
+			t.Fatalf("Trace should be reported once for request, got %v", paths)
+		}
+
+		if timing.Connect <= 0 {
+			t.Error("Connect timing should be recorded")
+		}
+
+		if timing.FirstByte <= 0 || timing.Total < timing.FirstByte {
+			t.Errorf("Wrong response timings: %+v", timing)
+		}
+
+		if timing.TLSHandshake != 0 {
+			t.Error("TLS handshake should not happen for plain HTTP")
+		}
+	})
+}
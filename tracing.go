@@ -0,0 +1,48 @@
+package apiary
+
+import "context"
+
+// Tracer is an interface of distributed tracer client starts span of every
+// request with. It is small enough to be adapted to OpenTelemetry or any
+// other tracing library without depending on it.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is an interface of span started by Tracer
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Span attributes set on every request span
+const (
+	AttributeAction     = "apiary.action"
+	AttributeMethod     = "http.method"
+	AttributeStatusCode = "http.status_code"
+)
+
+// startSpan starts request span with configured Tracer, returned function
+// finishes it with request outcome
+func (a *Apiary) startSpan(ctx context.Context, method string, path string) (context.Context, func(status int, err error)) {
+	if a.options.Tracer == nil {
+		return ctx, func(int, error) {}
+	}
+
+	ctx, span := a.options.Tracer.Start(ctx, "apiary "+method)
+	span.SetAttribute(AttributeAction, path)
+	span.SetAttribute(AttributeMethod, method)
+
+	return ctx, func(status int, err error) {
+		if status != 0 {
+			span.SetAttribute(AttributeStatusCode, status)
+		}
+
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		span.End()
+	}
+}
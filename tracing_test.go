@@ -0,0 +1,113 @@
+package apiary
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+type fakeSpan struct {
+	name       string
+	attributes map[string]interface{}
+	errors     []error
+	ended      bool
+}
+
+func (s *fakeSpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *fakeSpan) RecordError(err error) {
+	s.errors = append(s.errors, err)
+}
+
+func (s *fakeSpan) End() {
+	s.ended = true
+}
+
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span := &fakeSpan{
+		name:       name,
+		attributes: make(map[string]interface{}),
+	}
+	t.spans = append(t.spans, span)
+
+	return ctx, span
+}
+
+func TestApiary_Tracer(t *testing.T) {
+	t.Run("Record request span", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(200, `{"userId": "1"}`))
+
+		tracer := &fakeTracer{}
+		a := New(ApiaryOptions{
+			Token:  Token,
+			Tracer: tracer,
+		})
+
+		_, err := a.Me()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(tracer.spans) != 1 {
+			t.Fatalf("Expected 1 span, got %d", len(tracer.spans))
+		}
+
+		span := tracer.spans[0]
+		if !span.ended {
+			t.Error("Span should be ended")
+		}
+
+		if span.attributes[AttributeAction] != apiaryActionMe ||
+			span.attributes[AttributeMethod] != "GET" ||
+			span.attributes[AttributeStatusCode] != 200 {
+			t.Errorf("Wrong span attributes: %v", span.attributes)
+		}
+
+		if len(span.errors) != 0 {
+			t.Errorf("No error should be recorded: %v", span.errors)
+		}
+	})
+
+	t.Run("Record request error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewErrorResponder(errors.New("connection refused")))
+
+		tracer := &fakeTracer{}
+		a := New(ApiaryOptions{
+			Token:  Token,
+			Tracer: tracer,
+		})
+
+		_, err := a.Me()
+		if err == nil {
+			t.Fatal("Should return Error")
+		}
+
+		span := tracer.spans[0]
+		if len(span.errors) != 1 {
+			t.Errorf("Request error should be recorded: %v", span.errors)
+		}
+
+		if _, ok := span.attributes[AttributeStatusCode]; ok {
+			t.Error("Status code should not be set without response")
+		}
+	})
+}
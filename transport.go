@@ -0,0 +1,78 @@
+package apiary
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Middleware wraps client transport, for example to sign or trace requests
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// defaultTransport delegates to http.DefaultTransport at request time
+type defaultTransport struct{}
+
+func (defaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTransport return transport for client options, nil means
+// http.DefaultTransport is used. Transport created for client, which is not
+// shared with others, is returned as owned.
+func newTransport(opts ApiaryOptions) (transport http.RoundTripper, owned *http.Transport) {
+	if opts.RetryPolicy.ProxySelector != nil || opts.DialTimeout > 0 {
+		owned = newHTTPTransport(opts)
+		transport = owned
+	}
+
+	if len(opts.Middlewares) == 0 {
+		return
+	}
+
+	if transport == nil {
+		transport = defaultTransport{}
+	}
+
+	for i := len(opts.Middlewares) - 1; i >= 0; i-- {
+		transport = opts.Middlewares[i](transport)
+	}
+
+	return
+}
+
+// newHTTPTransport return transport with configured proxy and dial timeout
+func newHTTPTransport(opts ApiaryOptions) *http.Transport {
+	proxy := http.ProxyFromEnvironment
+	if opts.RetryPolicy.ProxySelector != nil {
+		proxy = proxySelector(opts.RetryPolicy.ProxySelector)
+	}
+
+	dialTimeout := 30 * time.Second
+	if opts.DialTimeout > 0 {
+		dialTimeout = opts.DialTimeout
+	}
+
+	return &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// proxySelector return transport proxy func choosing proxy by request attempt
+func proxySelector(selector func(attempt int) *url.URL) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if proxy := selector(attempt(req)); proxy != nil {
+			return proxy, nil
+		}
+
+		return http.ProxyFromEnvironment(req)
+	}
+}
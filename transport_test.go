@@ -0,0 +1,133 @@
+package apiary
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func Test_ProxySelector(t *testing.T) {
+	t.Run("Rotate proxy on retry", func(t *testing.T) {
+		hits := make([]int, 2)
+		proxies := make([]*url.URL, 2)
+
+		for i := range proxies {
+			i := i
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits[i]++
+				w.WriteHeader(http.StatusBadGateway)
+			}))
+			defer server.Close()
+
+			proxies[i], _ = url.Parse(server.URL)
+		}
+
+		a := New(ApiaryOptions{
+			Token: Token,
+			RetryPolicy: RetryPolicy{
+				MaxAttempts: 2,
+				ProxySelector: func(attempt int) *url.URL {
+					return proxies[(attempt-1)%len(proxies)]
+				},
+			},
+		})
+
+		_, err := a.Me()
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		if hits[0] != 1 || hits[1] != 1 {
+			t.Errorf("Each proxy should be used once, got %v", hits)
+		}
+	})
+
+	t.Run("Use default transport without selector", func(t *testing.T) {
+		a := New(ApiaryOptions{})
+
+		if a.client.Transport != nil {
+			t.Error("Default transport should be used")
+		}
+	})
+}
+
+func Test_DialTimeout(t *testing.T) {
+	t.Run("Fail fast on unroutable address", func(t *testing.T) {
+		a := New(ApiaryOptions{
+			Token:       Token,
+			BaseURL:     "http://10.255.255.1/",
+			Timeout:     10 * time.Second,
+			DialTimeout: 100 * time.Millisecond,
+		})
+
+		start := time.Now()
+		_, err := a.Me()
+
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Connection setup should fail within dial timeout, took %s", elapsed)
+		}
+	})
+
+	t.Run("Use provided client as is", func(t *testing.T) {
+		client := &http.Client{}
+		a := New(ApiaryOptions{
+			DialTimeout: time.Second,
+			HTTPClient:  client,
+		})
+
+		if a.client != client || client.Transport != nil {
+			t.Error("Provided client should be used as is")
+		}
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_Middlewares(t *testing.T) {
+	t.Run("Apply middlewares in order", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var header http.Header
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			header = req.Header
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+
+		middleware := func(name string) Middleware {
+			return func(next http.RoundTripper) http.RoundTripper {
+				return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					req.Header.Add("X-Middleware", name)
+					return next.RoundTrip(req)
+				})
+			}
+		}
+
+		a := New(ApiaryOptions{
+			Token:       Token,
+			Middlewares: []Middleware{middleware("first"), middleware("second")},
+		})
+
+		_, err := a.Me()
+		if err != nil {
+			t.Errorf("Error: %s", err.Error())
+		}
+
+		if strings.Join(header["X-Middleware"], ",") != "first,second" {
+			t.Errorf("Wrong middlewares order: %v", header["X-Middleware"])
+		}
+	})
+}
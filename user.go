@@ -0,0 +1,44 @@
+package apiary
+
+// CurrentUser return user information, fetched with Me() on first call and
+// cached for client lifetime. Concurrent first calls share one fetch. Use
+// RefreshCurrentUser() to fetch it again.
+func (a *Apiary) CurrentUser() (me *ApiaryMeResponse, err error) {
+	a.userMu.Lock()
+	defer a.userMu.Unlock()
+
+	if a.user != nil {
+		return copyUser(*a.user), nil
+	}
+
+	return a.refreshUser()
+}
+
+// RefreshCurrentUser fetches user information with Me() and replaces one
+// cached by CurrentUser(). Cache is kept when fetch fails.
+func (a *Apiary) RefreshCurrentUser() (me *ApiaryMeResponse, err error) {
+	a.userMu.Lock()
+	defer a.userMu.Unlock()
+
+	return a.refreshUser()
+}
+
+// refreshUser fetches user information into cache, a.userMu must be held
+func (a *Apiary) refreshUser() (me *ApiaryMeResponse, err error) {
+	user, err := a.Me()
+	if err != nil {
+		return
+	}
+
+	a.user = &user
+	return copyUser(user), nil
+}
+
+// copyUser return copy of user information not sharing teams with user
+func copyUser(user ApiaryMeResponse) *ApiaryMeResponse {
+	if user.Teams != nil {
+		user.Teams = append([]ApiaryTeam{}, user.Teams...)
+	}
+
+	return &user
+}
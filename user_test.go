@@ -0,0 +1,140 @@
+package apiary
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_CurrentUser(t *testing.T) {
+	t.Run("Cache user and refresh it", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionMe, func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, fmt.Sprintf(`{"userId": "1", "userName": "user%d"}`, requests)), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		me, err := a.CurrentUser()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		me, err = a.CurrentUser()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if requests != 1 || me.Name != "user1" {
+			t.Errorf("Second call should use cache, got %d requests and %s", requests, me.Name)
+		}
+
+		me, err = a.RefreshCurrentUser()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if requests != 2 || me.Name != "user2" {
+			t.Errorf("Refresh should fetch user again, got %d requests and %s", requests, me.Name)
+		}
+
+		me, _ = a.CurrentUser()
+		if requests != 2 || me.Name != "user2" {
+			t.Errorf("Refreshed user should be cached, got %d requests and %s", requests, me.Name)
+		}
+	})
+
+	t.Run("Return copy of cached user", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionMe, httpmock.NewStringResponder(200, `{"userId": "1", "teams": [{"teamId": "t1", "teamName": "First"}]}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		me, err := a.CurrentUser()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+		me.Teams[0].Name = "Changed"
+
+		me, _ = a.CurrentUser()
+		if me.Teams[0].Name != "First" {
+			t.Error("Changing returned user should not change cache")
+		}
+	})
+
+	t.Run("Share first fetch between concurrent calls", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var requests int32
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionMe, func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+			time.Sleep(20 * time.Millisecond)
+			return httpmock.NewStringResponse(200, `{"userId": "1"}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				if _, err := a.CurrentUser(); err != nil {
+					t.Errorf("Error: %s", err.Error())
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := atomic.LoadInt32(&requests); n != 1 {
+			t.Errorf("Expected 1 request, got %d", n)
+		}
+	})
+
+	t.Run("Keep cache on failed refresh", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionMe, httpmock.NewStringResponder(200, `{"userId": "1", "userName": "user"}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.CurrentUser()
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+apiaryActionMe, httpmock.NewStringResponder(500, `{}`))
+
+		_, err = a.RefreshCurrentUser()
+		if err == nil {
+			t.Error("Should return Error")
+		}
+
+		me, err := a.CurrentUser()
+		if err != nil || me.Name != "user" {
+			t.Errorf("Cached user should be kept, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,252 @@
+package apiary
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+)
+
+// ParserAnnotation is a struct of blueprint parser error or warning
+//
+// Description:
+// Type - "error" or "warning"
+// Code - parser code of annotation
+// Message - annotation message
+// Location - ranges of blueprint content annotation refers to
+type ParserAnnotation struct {
+	Type     string        `json:"type"`
+	Code     int           `json:"code"`
+	Message  string        `json:"message"`
+	Location []SourceRange `json:"location"`
+}
+
+// SourceRange is a range of blueprint content in bytes
+type SourceRange struct {
+	Index  int `json:"index"`
+	Length int `json:"length"`
+}
+
+// ValidationResult is a struct of answer to ValidateBlueprint() call. Parser
+// errors block publish, warnings don't.
+type ValidationResult struct {
+	Annotations []ParserAnnotation
+}
+
+// Errors return parser errors of blueprint
+func (r *ValidationResult) Errors() []ParserAnnotation {
+	return r.filter("error")
+}
+
+// Warnings return parser warnings of blueprint
+func (r *ValidationResult) Warnings() []ParserAnnotation {
+	return r.filter("warning")
+}
+
+// Blocking reports whether blueprint has parser errors, which make apiary.io
+// reject publish
+func (r *ValidationResult) Blocking() bool {
+	return len(r.Errors()) > 0
+}
+
+// CanPublish reports whether blueprint can be published, it may still have
+// warnings
+func (r *ValidationResult) CanPublish() bool {
+	return !r.Blocking()
+}
+
+func (r *ValidationResult) filter(kind string) []ParserAnnotation {
+	annotations := []ParserAnnotation{}
+	for _, annotation := range r.Annotations {
+		if annotation.Type == kind {
+			annotations = append(annotations, annotation)
+		}
+	}
+
+	return annotations
+}
+
+// ValidateBlueprint validates blueprint content with API Blueprint parser
+// service of ParserURL option, ErrNoParser is returned when it is not set.
+// Token is not sent to the service.
+//
+// Reference: https://github.com/apiaryio/api.apiblueprint.org
+func (a *Apiary) ValidateBlueprint(content []byte) (result *ValidationResult, err error) {
+	if a.options.ParserURL == "" {
+		err = ErrNoParser
+		return
+	}
+
+	headers := make(map[string]string)
+	headers["Content-Type"] = "text/vnd.apiblueprint"
+	headers["Accept"] = "application/vnd.apiblueprint.parseresult+json; version=2.2"
+
+	data, response, err := a.do(context.Background(), "POST", a.options.ParserURL, headers, content)
+	if err != nil {
+		return
+	}
+
+	var parsed struct {
+		Error    ParserAnnotation   `json:"error"`
+		Warnings []ParserAnnotation `json:"warnings"`
+	}
+
+	err = json.Unmarshal(data, &parsed)
+	if err != nil {
+		return
+	}
+
+	if parsed.Error.Code == 0 && parsed.Error.Message == "" {
+		err = checkOk(response)
+		if err != nil {
+			return
+		}
+	}
+
+	result = &ValidationResult{}
+	if parsed.Error.Code != 0 || parsed.Error.Message != "" {
+		parsed.Error.Type = "error"
+		result.Annotations = append(result.Annotations, parsed.Error)
+	}
+
+	for _, warning := range parsed.Warnings {
+		warning.Type = "warning"
+		result.Annotations = append(result.Annotations, warning)
+	}
+
+	return
+}
+
+// PublishBlueprintStrict validates blueprint and publish it only when it has
+// no parser errors (ErrInvalidBlueprint returned) and no more than
+// maxWarnings warnings (ErrTooManyWarnings returned).
+func (a *Apiary) PublishBlueprintStrict(name string, content []byte, maxWarnings int) (published bool, err error) {
+	err = a.writable()
+	if err != nil {
+		return
+	}
+
+	result, err := a.ValidateBlueprint(content)
+	if err != nil {
+		return
+	}
+
+	if result.Blocking() {
+		err = ErrInvalidBlueprint
+		return
+	}
+
+	if len(result.Warnings()) > maxWarnings {
+		err = ErrTooManyWarnings
+		return
+	}
+
+	return a.PublishBlueprint(name, content)
+}
+
+// ValidateAll fetches every blueprint user can access and validates it,
+// returning parser annotations by API subdomain. Blueprints are processed
+// concurrently, no more than Parallelism at once. APIs which couldn't be
+// fetched or validated are reported with ApiErrors, teams which APIs couldn't
+// be listed with TeamErrors, and both with *PartialErrors.
+func (a *Apiary) ValidateAll() (annotations map[string][]ParserAnnotation, err error) {
+	apis, err := a.GetAllApis()
+	if _, partial := err.(TeamErrors); err != nil && !partial {
+		return
+	}
+
+	annotations = make(map[string][]ParserAnnotation)
+	errs := make(ApiErrors)
+	var mu sync.Mutex
+
+	a.parallel(len(apis.Apis), func(i int) {
+		subdomain := apis.Apis[i].Subdomain
+
+		result, apiErr := a.validateApi(subdomain)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if apiErr != nil {
+			errs[subdomain] = apiErr
+			return
+		}
+
+		annotations[subdomain] = result.Annotations
+	})
+
+	if len(errs) > 0 {
+		if teamErrs, partial := err.(TeamErrors); partial {
+			err = &PartialErrors{Teams: teamErrs, Apis: errs}
+		} else {
+			err = errs
+		}
+	}
+
+	return
+}
+
+// FileErrors is an error with errors of files which failed in a batch call,
+// by file path
+type FileErrors map[string]error
+
+func (e FileErrors) Error() string {
+	return joinErrors("Failed files", e)
+}
+
+// ValidateFiles reads blueprint files and validates them with API Blueprint
+// parser service, returning parser annotations by file path. Without
+// ParserURL option files are checked offline with CheckBlueprintSyntax().
+// Files are processed concurrently, no more than Parallelism at once. Files
+// which couldn't be read or validated are reported with FileErrors,
+// annotations of other files are returned along with it.
+func (a *Apiary) ValidateFiles(paths []string) (annotations map[string][]ParserAnnotation, err error) {
+	annotations = make(map[string][]ParserAnnotation)
+	errs := make(FileErrors)
+	var mu sync.Mutex
+
+	a.parallel(len(paths), func(i int) {
+		path := paths[i]
+
+		result, fileErr := a.validateFile(path)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if fileErr != nil {
+			errs[path] = fileErr
+			return
+		}
+
+		annotations[path] = result.Annotations
+	})
+
+	if len(errs) > 0 {
+		err = errs
+	}
+
+	return
+}
+
+func (a *Apiary) validateFile(path string) (result *ValidationResult, err error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	if a.options.ParserURL == "" {
+		result = &ValidationResult{Annotations: CheckBlueprintSyntax(content)}
+		return
+	}
+
+	return a.ValidateBlueprint(content)
+}
+
+func (a *Apiary) validateApi(subdomain string) (result *ValidationResult, err error) {
+	blueprint, err := a.FetchBlueprint(subdomain)
+	if err != nil {
+		return
+	}
+
+	return a.ValidateBlueprint([]byte(blueprint.Code))
+}
@@ -0,0 +1,413 @@
+package apiary
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+const parseResultWarnings = `{
+	"_version": "2.2",
+	"error": {"code": 0, "message": "", "location": []},
+	"warnings": [
+		{"code": 6, "message": "empty response", "location": [{"index": 10, "length": 5}]},
+		{"code": 8, "message": "empty request", "location": []}
+	]
+}`
+
+const parseResultError = `{
+	"_version": "2.2",
+	"error": {"code": 2, "message": "unexpected header", "location": [{"index": 0, "length": 4}]},
+	"warnings": []
+}`
+
+func TestApiary_ValidateBlueprint(t *testing.T) {
+	t.Run("Parse warnings", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var authorized bool
+		httpmock.RegisterResponder("POST", ApiBlueprintParserURL, func(req *http.Request) (*http.Response, error) {
+			authorized = req.Header.Get("Authorization") != "" || req.Header.Get("Authentication") != ""
+			return httpmock.NewStringResponse(200, parseResultWarnings), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:     Token,
+			ParserURL: ApiBlueprintParserURL,
+		})
+
+		r, err := a.ValidateBlueprint(ValidBlueprint)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(r.Warnings()) != 2 || len(r.Errors()) != 0 {
+			t.Errorf("Wrong annotations: %+v", r.Annotations)
+		}
+
+		w := r.Warnings()[0]
+		if w.Code != 6 || w.Message != "empty response" || len(w.Location) != 1 || w.Location[0].Index != 10 {
+			t.Errorf("Wrong warning: %+v", w)
+		}
+
+		if authorized {
+			t.Error("Token should not be sent to parser service")
+		}
+	})
+
+	t.Run("Parse error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("POST", ApiBlueprintParserURL, httpmock.NewStringResponder(422, parseResultError))
+
+		a := New(ApiaryOptions{
+			Token:     Token,
+			ParserURL: ApiBlueprintParserURL,
+		})
+
+		r, err := a.ValidateBlueprint([]byte("some invalid data"))
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(r.Errors()) != 1 || r.Errors()[0].Message != "unexpected header" {
+			t.Errorf("Wrong annotations: %+v", r.Annotations)
+		}
+	})
+
+	t.Run("Return error on bad response", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("POST", ApiBlueprintParserURL, httpmock.NewStringResponder(500, `{}`))
+
+		a := New(ApiaryOptions{
+			Token:     Token,
+			ParserURL: ApiBlueprintParserURL,
+		})
+
+		_, err := a.ValidateBlueprint(ValidBlueprint)
+		if err == nil {
+			t.Error("Should return Error")
+		}
+	})
+
+	t.Run("Require parser URL", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, parseResultWarnings), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		_, err := a.ValidateBlueprint(ValidBlueprint)
+		if err != ErrNoParser {
+			t.Errorf("Expected ErrNoParser, got: %v", err)
+		}
+
+		if requests != 0 {
+			t.Error("Blueprint should not be sent anywhere")
+		}
+	})
+
+	t.Run("Skip apiary.io hooks", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		var header http.Header
+		httpmock.RegisterResponder("POST", ApiBlueprintParserURL, func(req *http.Request) (*http.Response, error) {
+			header = req.Header
+			return httpmock.NewStringResponse(200, parseResultWarnings), nil
+		})
+
+		intercepted := false
+		a := New(ApiaryOptions{
+			Token:     Token,
+			ParserURL: ApiBlueprintParserURL,
+			RequestInterceptor: func(req *http.Request) error {
+				intercepted = true
+				return nil
+			},
+			RequestSigner: func(method, path string, body []byte) (string, string, error) {
+				return "X-Signature", "signed", nil
+			},
+		})
+
+		_, err := a.ValidateBlueprint(ValidBlueprint)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if intercepted || header.Get("X-Signature") != "" {
+			t.Error("Hooks of apiary.io requests should not be applied to parser service")
+		}
+	})
+}
+
+func TestValidationResult_Blocking(t *testing.T) {
+	validate := func(parseResult string) *ValidationResult {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("POST", ApiBlueprintParserURL, httpmock.NewStringResponder(200, parseResult))
+
+		a := New(ApiaryOptions{
+			Token:     Token,
+			ParserURL: ApiBlueprintParserURL,
+		})
+
+		r, err := a.ValidateBlueprint(ValidBlueprint)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		return r
+	}
+
+	t.Run("Block publish on error", func(t *testing.T) {
+		r := validate(parseResultError)
+
+		if !r.Blocking() || r.CanPublish() {
+			t.Error("Parser error should block publish")
+		}
+	})
+
+	t.Run("Allow publish with warnings", func(t *testing.T) {
+		r := validate(parseResultWarnings)
+
+		if r.Blocking() || !r.CanPublish() {
+			t.Error("Parser warnings should not block publish")
+		}
+	})
+}
+
+func TestApiary_PublishBlueprintStrict(t *testing.T) {
+	publish := func(parseResult string, maxWarnings int) (bool, int, error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		published := 0
+		httpmock.RegisterResponder("POST", ApiBlueprintParserURL, httpmock.NewStringResponder(200, parseResult))
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			published++
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:     Token,
+			ParserURL: ApiBlueprintParserURL,
+		})
+
+		ok, err := a.PublishBlueprintStrict(Repository, ValidBlueprint, maxWarnings)
+		return ok, published, err
+	}
+
+	t.Run("Publish with warnings below limit", func(t *testing.T) {
+		ok, published, err := publish(parseResultWarnings, 2)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !ok || published != 1 {
+			t.Error("Not published")
+		}
+	})
+
+	t.Run("Abort with warnings above limit", func(t *testing.T) {
+		ok, published, err := publish(parseResultWarnings, 1)
+
+		if err != ErrTooManyWarnings {
+			t.Error("Should return ErrTooManyWarnings")
+		}
+
+		if ok || published != 0 {
+			t.Error("Published")
+		}
+	})
+
+	t.Run("Abort on parser error", func(t *testing.T) {
+		ok, published, err := publish(parseResultError, 10)
+
+		if err != ErrInvalidBlueprint {
+			t.Error("Should return ErrInvalidBlueprint")
+		}
+
+		if ok || published != 0 {
+			t.Error("Published")
+		}
+	})
+}
+
+func TestApiary_ValidateAll(t *testing.T) {
+	validateAll := func(mock func()) (map[string][]ParserAnnotation, error) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockCatalog()
+		mock()
+
+		blueprints := map[string]string{
+			"personal": "clean",
+			"shared":   "broken",
+			"first":    "warning",
+		}
+
+		for subdomain, code := range blueprints {
+			body, _ := json.Marshal(map[string]string{"code": code})
+			httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, subdomain), httpmock.NewBytesResponder(200, body))
+		}
+		httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionFetchBlueprint, "second"), httpmock.NewStringResponder(404, `{}`))
+
+		httpmock.RegisterResponder("POST", ApiBlueprintParserURL, func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+
+			switch string(body) {
+			case "broken":
+				return httpmock.NewStringResponse(422, parseResultError), nil
+			case "warning":
+				return httpmock.NewStringResponse(200, parseResultWarnings), nil
+			default:
+				return httpmock.NewStringResponse(200, `{"error": {"code": 0}, "warnings": []}`), nil
+			}
+		})
+
+		a := New(ApiaryOptions{
+			Token:       Token,
+			ParserURL:   ApiBlueprintParserURL,
+			Parallelism: 2,
+		})
+
+		return a.ValidateAll()
+	}
+
+	t.Run("Validate every blueprint", func(t *testing.T) {
+		r, err := validateAll(func() {})
+
+		errs, ok := err.(ApiErrors)
+		if !ok || len(errs) != 1 || errs["second"] == nil {
+			t.Errorf("Unreachable blueprint should be reported, got %v", err)
+		}
+
+		if len(r) != 3 {
+			t.Fatalf("Expected 3 validated blueprints, got %d", len(r))
+		}
+
+		if len(r["personal"]) != 0 {
+			t.Errorf("Clean blueprint has annotations: %+v", r["personal"])
+		}
+
+		if len(r["shared"]) != 1 || r["shared"][0].Type != "error" {
+			t.Errorf("Wrong broken blueprint annotations: %+v", r["shared"])
+		}
+
+		if len(r["first"]) != 2 || r["first"][0].Type != "warning" {
+			t.Errorf("Wrong warning blueprint annotations: %+v", r["first"])
+		}
+	})
+
+	t.Run("Report team and API errors", func(t *testing.T) {
+		_, err := validateAll(func() {
+			httpmock.RegisterResponder("GET", ApiaryAPIURL+fmt.Sprintf(apiaryActionGetTeamApis, "t1"), httpmock.NewStringResponder(403, `{}`))
+		})
+
+		errs, ok := err.(*PartialErrors)
+		if !ok {
+			t.Fatalf("Expected *PartialErrors, got %v", err)
+		}
+
+		if len(errs.Teams) != 1 || errs.Teams["t1"] == nil {
+			t.Errorf("Failed team should be reported: %v", errs.Teams)
+		}
+
+		if len(errs.Apis) != 1 || errs.Apis["second"] == nil {
+			t.Errorf("Unreachable blueprint should be reported: %v", errs.Apis)
+		}
+	})
+}
+
+func TestApiary_ValidateFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apiary")
+	if err != nil {
+		t.Fatalf("Error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	valid := filepath.Join(dir, "valid.apib")
+	invalid := filepath.Join(dir, "invalid.apib")
+	missing := filepath.Join(dir, "missing.apib")
+	ioutil.WriteFile(valid, ValidBlueprint, 0644)
+	ioutil.WriteFile(invalid, []byte("broken"), 0644)
+
+	t.Run("Validate with parser", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterResponder("POST", ApiBlueprintParserURL, func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+
+			if string(body) == "broken" {
+				return httpmock.NewStringResponse(422, parseResultError), nil
+			}
+
+			return httpmock.NewStringResponse(200, `{"error": {"code": 0}, "warnings": []}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token:     Token,
+			ParserURL: ApiBlueprintParserURL,
+		})
+
+		r, err := a.ValidateFiles([]string{valid, invalid, missing})
+
+		errs, ok := err.(FileErrors)
+		if !ok || len(errs) != 1 || !os.IsNotExist(errs[missing]) {
+			t.Errorf("Unreadable file should be reported, got %v", err)
+		}
+
+		if len(r) != 2 {
+			t.Fatalf("Expected annotations of 2 files, got %v", r)
+		}
+
+		if len(r[valid]) != 0 {
+			t.Errorf("Valid file should have no annotations: %+v", r[valid])
+		}
+
+		if len(r[invalid]) != 1 || r[invalid][0].Type != "error" {
+			t.Errorf("Invalid file should have error: %+v", r[invalid])
+		}
+	})
+
+	t.Run("Check syntax without parser", func(t *testing.T) {
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		r, err := a.ValidateFiles([]string{valid, invalid})
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if len(r[valid]) != 0 {
+			t.Errorf("Valid file should have no annotations: %+v", r[valid])
+		}
+
+		result := ValidationResult{Annotations: r[invalid]}
+		if len(result.Errors()) != 1 || len(result.Warnings()) != 1 {
+			t.Errorf("Invalid file should have syntax annotations: %+v", r[invalid])
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package apiary
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WatchBlueprint polls blueprint every interval and sends its content into
+// returned channel when it changes. Unchanged blueprints are detected with
+// ETag/If-None-Match, or by comparing content when server sends no ETag.
+// Errors are sent into error channel and don't stop watching. Both channels
+// are closed when ctx is done. When interval is not positive blueprint is
+// not polled, ErrInvalidInterval is sent and channels are closed.
+func (a *Apiary) WatchBlueprint(ctx context.Context, name string, interval time.Duration) (<-chan []byte, <-chan error) {
+	blueprints := make(chan []byte)
+	errs := make(chan error)
+
+	go func() {
+		defer close(blueprints)
+		defer close(errs)
+
+		if interval <= 0 {
+			select {
+			case errs <- ErrInvalidInterval:
+			case <-ctx.Done():
+			}
+
+			return
+		}
+
+		var etag, last string
+		var seen bool
+		for {
+			blueprint, tag, modified, err := a.fetchBlueprintIfNoneMatch(ctx, name, etag)
+
+			switch {
+			case err != nil:
+				select {
+				case errs <- err:
+				case <-ctx.Done():
+					return
+				}
+			case modified && (!seen || blueprint.Code != last):
+				etag, last, seen = tag, blueprint.Code, true
+
+				select {
+				case blueprints <- []byte(blueprint.Code):
+				case <-ctx.Done():
+					return
+				}
+			case modified:
+				etag = tag
+			}
+
+			select {
+			case <-a.clock().After(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return blueprints, errs
+}
+
+// BlueprintChanged reports whether local content differs from published
+// blueprint. SHA-256 of local content is sent as If-None-Match ETag, so when
+// server computes same ETag unchanged blueprint is not downloaded. Otherwise
+// fetched content is compared with local one, both with normalized line
+// endings.
+func (a *Apiary) BlueprintChanged(name string, local []byte) (changed bool, err error) {
+	return a.blueprintChanged(context.Background(), name, local)
+}
+
+// blueprintChanged is BlueprintChanged() canceling requests when ctx is done
+func (a *Apiary) blueprintChanged(ctx context.Context, name string, local []byte) (changed bool, err error) {
+	etag := contentETag(local)
+	blueprint, tag, modified, err := a.fetchBlueprintIfNoneMatch(ctx, name, etag)
+	if err != nil || !modified || tag == etag {
+		return
+	}
+
+	changed = !bytes.Equal(NormalizeBlueprint([]byte(blueprint.Code)), NormalizeBlueprint(local))
+	return
+}
+
+// contentETag return quoted SHA-256 of content
+func contentETag(content []byte) string {
+	return fmt.Sprintf(`"%x"`, sha256.Sum256(content))
+}
+
+// fetchBlueprintIfNoneMatch fetches blueprint unless it still matches etag,
+// modified is false when server responded with 304 Not Modified
+func (a *Apiary) fetchBlueprintIfNoneMatch(ctx context.Context, name string, etag string) (blueprint *ApiaryFetchResponse, tag string, modified bool, err error) {
+	headers := make(map[string]string)
+	if etag != "" {
+		headers["If-None-Match"] = etag
+	}
+
+	uri := fmt.Sprintf(apiaryActionFetchBlueprint, name)
+	data, response, err := a.sendBlueprintRequest(ctx, uri, headers)
+	if response != nil && response.StatusCode == http.StatusNotModified {
+		return nil, etag, false, nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	err = checkOk(response)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &blueprint)
+	if err != nil {
+		return
+	}
+
+	return blueprint, response.Header.Get("ETag"), true, nil
+}
@@ -0,0 +1,180 @@
+package apiary
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestApiary_WatchBlueprint(t *testing.T) {
+	t.Run("Emit only changed blueprint", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		polled := make(chan string, 10)
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			select {
+			case polled <- req.Header.Get("If-None-Match"):
+			default:
+			}
+
+			if req.Header.Get("If-None-Match") == `"v1"` {
+				return httpmock.NewStringResponse(304, ``), nil
+			}
+
+			response := httpmock.NewStringResponse(200, `{"code": "FORMAT: 1A"}`)
+			response.Header.Set("ETag", `"v1"`)
+			return response, nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		blueprints, errs := a.WatchBlueprint(ctx, Repository, time.Millisecond)
+
+		select {
+		case b := <-blueprints:
+			if string(b) != "FORMAT: 1A" {
+				t.Errorf("Wrong blueprint emitted: %s", b)
+			}
+		case err := <-errs:
+			t.Fatalf("Error: %s", err.Error())
+		case <-time.After(time.Second):
+			t.Fatal("Blueprint was not emitted")
+		}
+
+		<-polled
+		if tag := <-polled; tag != `"v1"` {
+			t.Errorf("Second poll should send If-None-Match, got %q", tag)
+		}
+
+		cancel()
+		for b := range blueprints {
+			t.Errorf("Unchanged blueprint emitted: %s", b)
+		}
+	})
+
+	t.Run("Close channels on cancel", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		httpmock.RegisterNoResponder(httpmock.NewStringResponder(500, `{}`))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		blueprints, errs := a.WatchBlueprint(ctx, Repository, time.Hour)
+
+		if err := <-errs; err == nil {
+			t.Error("Should return Error")
+		}
+
+		cancel()
+
+		if _, ok := <-blueprints; ok {
+			t.Error("Blueprints channel should be closed")
+		}
+
+		if _, ok := <-errs; ok {
+			t.Error("Errors channel should be closed")
+		}
+	})
+
+	t.Run("Reject non-positive interval", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		requests := 0
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(200, `{"code": "FORMAT: 1A"}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		blueprints, errs := a.WatchBlueprint(context.Background(), Repository, 0)
+		if err := <-errs; err != ErrInvalidInterval {
+			t.Errorf("Expected ErrInvalidInterval, got: %v", err)
+		}
+
+		if _, ok := <-blueprints; ok {
+			t.Error("Blueprints channel should be closed")
+		}
+
+		if requests != 0 {
+			t.Errorf("Blueprint should not be polled, got %d requests", requests)
+		}
+	})
+}
+
+func TestApiary_BlueprintChanged(t *testing.T) {
+	t.Run("Detect unchanged blueprint by hash", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		downloaded := false
+		httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("If-None-Match") == contentETag(ValidBlueprint) {
+				return httpmock.NewStringResponse(304, ``), nil
+			}
+
+			downloaded = true
+			return httpmock.NewStringResponse(200, `{"code": ""}`), nil
+		})
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		changed, err := a.BlueprintChanged(Repository, ValidBlueprint)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if changed || downloaded {
+			t.Error("Matching hash should report unchanged blueprint without download")
+		}
+	})
+
+	t.Run("Fallback to fetch and compare", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		code, _ := json.Marshal(map[string]string{
+			"code": string(ValidBlueprint),
+		})
+		httpmock.RegisterNoResponder(httpmock.NewBytesResponder(200, code))
+
+		a := New(ApiaryOptions{
+			Token: Token,
+		})
+
+		changed, err := a.BlueprintChanged(Repository, ValidBlueprint)
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if changed {
+			t.Error("Same content should be unchanged")
+		}
+
+		changed, err = a.BlueprintChanged(Repository, []byte("FORMAT: 1A\n# Changed"))
+		if err != nil {
+			t.Fatalf("Error: %s", err.Error())
+		}
+
+		if !changed {
+			t.Error("Different content should be changed")
+		}
+	})
+}